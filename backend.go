@@ -0,0 +1,520 @@
+package main
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	providerschema "github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/int64planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+)
+
+// backends are the storage backends the provider can be configured for.
+var backends = []string{"cassandra", "cosmos", "dynamo", "jdbc", "multi-storage"}
+
+// backendBlockNames lists the storage-specific option blocks. Each block is named after its backend.
+var backendBlockNames = []string{"cassandra", "dynamo", "cosmos"}
+
+// replicationStrategies are the replication strategies of Cassandra keyspaces.
+var replicationStrategies = []string{"SimpleStrategy", "NetworkTopologyStrategy"}
+
+// compactionStrategies are the compaction strategies of Cassandra tables, by the short
+// names ScalarDB accepts.
+var compactionStrategies = []string{"STCS", "LCS", "TWCS"}
+
+// cassandraNamespaceModel is the cassandra block of scalardb_namespace.
+type cassandraNamespaceModel struct {
+	ReplicationStrategy types.String `tfsdk:"replication_strategy"`
+	ReplicationFactor   types.Int64  `tfsdk:"replication_factor"`
+}
+
+func (m cassandraNamespaceModel) cassandraOptions() *scalardb.CassandraOptions {
+	return &scalardb.CassandraOptions{
+		ReplicationStrategy: m.ReplicationStrategy.ValueString(),
+		ReplicationFactor:   int(m.ReplicationFactor.ValueInt64()),
+	}
+}
+
+// withEffectiveOptions returns the block with the attributes that are not configured set
+// to the effective options.
+func (m cassandraNamespaceModel) withEffectiveOptions(options map[string]string) cassandraNamespaceModel {
+	m.ReplicationStrategy = effectiveString(m.ReplicationStrategy, options, "replication-strategy", "SimpleStrategy")
+	m.ReplicationFactor = effectiveInt64(m.ReplicationFactor, options, "replication-factor", 3)
+	return m
+}
+
+// cassandraTableModel is the cassandra block of scalardb_table.
+type cassandraTableModel struct {
+	CompactionStrategy types.String `tfsdk:"compaction_strategy"`
+}
+
+func (m cassandraTableModel) cassandraOptions() *scalardb.CassandraOptions {
+	return &scalardb.CassandraOptions{
+		CompactionStrategy: m.CompactionStrategy.ValueString(),
+	}
+}
+
+// withEffectiveOptions returns the block with the attributes that are not configured set
+// to the effective options.
+func (m cassandraTableModel) withEffectiveOptions(options map[string]string) cassandraTableModel {
+	m.CompactionStrategy = effectiveString(m.CompactionStrategy, options, "compaction-strategy", "STCS")
+	return m
+}
+
+// cassandraModel is implemented by the cassandra blocks of namespaces and tables.
+type cassandraModel[C any] interface {
+	cassandraOptions() *scalardb.CassandraOptions
+	withEffectiveOptions(options map[string]string) C
+}
+
+// dynamoModel is the dynamo block.
+type dynamoModel struct {
+	RU        types.Int64 `tfsdk:"ru"`
+	NoScaling types.Bool  `tfsdk:"no_scaling"`
+	NoBackup  types.Bool  `tfsdk:"no_backup"`
+}
+
+// cosmosModel is the cosmos block.
+type cosmosModel struct {
+	RU types.Int64 `tfsdk:"ru"`
+}
+
+// cassandraNamespaceBlock returns the schema of the cassandra block of scalardb_namespace.
+func cassandraNamespaceBlock() schema.ListNestedBlock {
+	return backendBlock("cassandra", "Cassandra-specific options for the namespace.", map[string]schema.Attribute{
+		"replication_strategy": schema.StringAttribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "The replication strategy of the keyspace. Defaults to the provider-level default, or SimpleStrategy.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(replicationStrategies...),
+			},
+		},
+		"replication_factor": schema.Int64Attribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "The replication factor of the keyspace. Defaults to the provider-level default, or 3.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+	})
+}
+
+// cassandraTableBlock returns the schema of the cassandra block of scalardb_table.
+func cassandraTableBlock() schema.ListNestedBlock {
+	return backendBlock("cassandra", "Cassandra-specific options for the table.", map[string]schema.Attribute{
+		"compaction_strategy": schema.StringAttribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "The compaction strategy of the table. Defaults to the provider-level default, or STCS.",
+			PlanModifiers: []planmodifier.String{
+				stringplanmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.String{
+				stringvalidator.OneOf(compactionStrategies...),
+			},
+		},
+	})
+}
+
+// dynamoBlock returns the schema of the dynamo block.
+func dynamoBlock() schema.ListNestedBlock {
+	return backendBlock("dynamo", "DynamoDB-specific options.", map[string]schema.Attribute{
+		"ru": schema.Int64Attribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "The read and write capacity units. Defaults to the provider-level default, or 10.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"no_scaling": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "Whether to disable auto-scaling. Defaults to the provider-level default, or false.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.UseStateForUnknown(),
+			},
+		},
+		"no_backup": schema.BoolAttribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "Whether to disable continuous backup. Defaults to the provider-level default, or false.",
+			PlanModifiers: []planmodifier.Bool{
+				boolplanmodifier.UseStateForUnknown(),
+			},
+		},
+	})
+}
+
+// cosmosBlock returns the schema of the cosmos block.
+func cosmosBlock() schema.ListNestedBlock {
+	return backendBlock("cosmos", "Cosmos DB-specific options.", map[string]schema.Attribute{
+		"ru": schema.Int64Attribute{
+			Optional:    true,
+			Computed:    true,
+			Description: "The request units. Defaults to the provider-level default, or 400.",
+			PlanModifiers: []planmodifier.Int64{
+				int64planmodifier.UseStateForUnknown(),
+			},
+			Validators: []validator.Int64{
+				int64validator.AtLeast(400),
+			},
+		},
+	})
+}
+
+// backendBlock returns the schema of a storage-specific option block.
+// Changing the block replaces the resource, since creation options cannot be altered.
+func backendBlock(name, description string, attributes map[string]schema.Attribute) schema.ListNestedBlock {
+	var conflicts []path.Expression
+	for _, other := range backendBlockNames {
+		if other != name {
+			conflicts = append(conflicts, path.MatchRoot(other))
+		}
+	}
+
+	return schema.ListNestedBlock{
+		Description: description,
+		Validators: []validator.List{
+			listvalidator.SizeAtMost(1),
+			listvalidator.ConflictsWith(conflicts...),
+		},
+		PlanModifiers: []planmodifier.List{
+			requiresReplaceIfListChanged(),
+		},
+		NestedObject: schema.NestedBlockObject{
+			Attributes: attributes,
+		},
+	}
+}
+
+// providerCassandraNamespaceBlock returns the cassandra block of default_namespace_options.
+func providerCassandraNamespaceBlock() providerschema.ListNestedBlock {
+	return providerBackendBlock("Cassandra-specific options for the namespace.", map[string]providerschema.Attribute{
+		"replication_strategy": providerschema.StringAttribute{
+			Optional:    true,
+			Description: "The replication strategy of the keyspace. Defaults to SimpleStrategy.",
+			Validators: []validator.String{
+				stringvalidator.OneOf(replicationStrategies...),
+			},
+		},
+		"replication_factor": providerschema.Int64Attribute{
+			Optional:    true,
+			Description: "The replication factor of the keyspace. Defaults to 3.",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+	})
+}
+
+// providerCassandraTableBlock returns the cassandra block of default_table_options.
+func providerCassandraTableBlock() providerschema.ListNestedBlock {
+	return providerBackendBlock("Cassandra-specific options for the table.", map[string]providerschema.Attribute{
+		"compaction_strategy": providerschema.StringAttribute{
+			Optional:    true,
+			Description: "The compaction strategy of the table. Defaults to STCS.",
+			Validators: []validator.String{
+				stringvalidator.OneOf(compactionStrategies...),
+			},
+		},
+	})
+}
+
+// providerDynamoBlock returns the dynamo block of the provider-level default options.
+func providerDynamoBlock() providerschema.ListNestedBlock {
+	return providerBackendBlock("DynamoDB-specific options.", map[string]providerschema.Attribute{
+		"ru": providerschema.Int64Attribute{
+			Optional:    true,
+			Description: "The read and write capacity units. Defaults to 10.",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(1),
+			},
+		},
+		"no_scaling": providerschema.BoolAttribute{
+			Optional:    true,
+			Description: "Whether to disable auto-scaling.",
+		},
+		"no_backup": providerschema.BoolAttribute{
+			Optional:    true,
+			Description: "Whether to disable continuous backup.",
+		},
+	})
+}
+
+// providerCosmosBlock returns the cosmos block of the provider-level default options.
+func providerCosmosBlock() providerschema.ListNestedBlock {
+	return providerBackendBlock("Cosmos DB-specific options.", map[string]providerschema.Attribute{
+		"ru": providerschema.Int64Attribute{
+			Optional:    true,
+			Description: "The request units. Defaults to 400.",
+			Validators: []validator.Int64{
+				int64validator.AtLeast(400),
+			},
+		},
+	})
+}
+
+// providerBackendBlock returns the schema of a storage-specific option block in the provider configuration.
+// Provider schemas have no defaults, so the defaults are applied when the options are expanded.
+func providerBackendBlock(description string, attributes map[string]providerschema.Attribute) providerschema.ListNestedBlock {
+	return providerschema.ListNestedBlock{
+		Description: description,
+		Validators: []validator.List{
+			listvalidator.SizeAtMost(1),
+		},
+		NestedObject: providerschema.NestedBlockObject{
+			Attributes: attributes,
+		},
+	}
+}
+
+// defaultOptionsBlock returns the schema of a provider-level default options block.
+func defaultOptionsBlock(description string, cassandra providerschema.ListNestedBlock) providerschema.ListNestedBlock {
+	return providerschema.ListNestedBlock{
+		Description: description,
+		Validators: []validator.List{
+			listvalidator.SizeAtMost(1),
+		},
+		NestedObject: providerschema.NestedBlockObject{
+			Attributes: map[string]providerschema.Attribute{
+				"options": providerschema.MapAttribute{
+					ElementType: types.StringType,
+					Optional:    true,
+					Description: "Additional creation options passed verbatim to ScalarDB.",
+					Validators:  optionsValidators(),
+				},
+			},
+			Blocks: map[string]providerschema.Block{
+				"cassandra": cassandra,
+				"dynamo":    providerDynamoBlock(),
+				"cosmos":    providerCosmosBlock(),
+			},
+		},
+	}
+}
+
+// defaultNamespaceOptionsModel is the default_namespace_options block of the provider.
+type defaultNamespaceOptionsModel struct {
+	Cassandra []cassandraNamespaceModel `tfsdk:"cassandra"`
+	Dynamo    []dynamoModel             `tfsdk:"dynamo"`
+	Cosmos    []cosmosModel             `tfsdk:"cosmos"`
+	Options   types.Map                 `tfsdk:"options"`
+}
+
+// defaultTableOptionsModel is the default_table_options block of the provider.
+type defaultTableOptionsModel struct {
+	Cassandra []cassandraTableModel `tfsdk:"cassandra"`
+	Dynamo    []dynamoModel         `tfsdk:"dynamo"`
+	Cosmos    []cosmosModel         `tfsdk:"cosmos"`
+	Options   types.Map             `tfsdk:"options"`
+}
+
+// expandDefaultNamespaceOptions builds the creation options of the default_namespace_options block.
+func expandDefaultNamespaceOptions(blocks []defaultNamespaceOptionsModel, backend string) (map[string]string, error) {
+	if len(blocks) == 0 {
+		return map[string]string{}, nil
+	}
+	b := blocks[0]
+	return expandDefaultOptions(backend, blocksSet(len(b.Cassandra), len(b.Dynamo), len(b.Cosmos)),
+		expandStorageOptions(b.Cassandra, b.Dynamo, b.Cosmos), b.Options)
+}
+
+// expandDefaultTableOptions builds the creation options of the default_table_options block.
+func expandDefaultTableOptions(blocks []defaultTableOptionsModel, backend string) (map[string]string, error) {
+	if len(blocks) == 0 {
+		return map[string]string{}, nil
+	}
+	b := blocks[0]
+	return expandDefaultOptions(backend, blocksSet(len(b.Cassandra), len(b.Dynamo), len(b.Cosmos)),
+		expandStorageOptions(b.Cassandra, b.Dynamo, b.Cosmos), b.Options)
+}
+
+// expandDefaultOptions checks the option blocks of a provider-level default options block
+// against the backend and merges them with its options.
+func expandDefaultOptions(backend string, set []string, storage scalardb.StorageOptions, options types.Map) (map[string]string, error) {
+	for _, blockName := range set {
+		if backend != "" && backend != "multi-storage" && blockName != backend {
+			return nil, fmt.Errorf("the %s block cannot be used because the provider is configured for the %s backend", blockName, backend)
+		}
+	}
+	if len(set) > 1 {
+		return nil, fmt.Errorf("only one of %s can be set", strings.Join(set, ", "))
+	}
+
+	return mergeOptions(storage.Map(), expandOptions(options)), nil
+}
+
+// blocksSet returns the names of the option blocks that are set, given the number of
+// cassandra, dynamo and cosmos blocks.
+func blocksSet(cassandra, dynamo, cosmos int) []string {
+	var set []string
+	for i, n := range []int{cassandra, dynamo, cosmos} {
+		if n > 0 {
+			set = append(set, backendBlockNames[i])
+		}
+	}
+	return set
+}
+
+// expandStorageOptions builds the storage-specific options from the option blocks.
+// Attributes that are null or unknown are left out, so that the provider-level defaults
+// and then the defaults of ScalarDB apply to them.
+func expandStorageOptions[C cassandraModel[C]](cassandra []C, dynamo []dynamoModel, cosmos []cosmosModel) scalardb.StorageOptions {
+	var storage scalardb.StorageOptions
+
+	if len(cassandra) > 0 {
+		storage.Cassandra = cassandra[0].cassandraOptions()
+	}
+
+	if len(dynamo) > 0 {
+		storage.Dynamo = &scalardb.DynamoOptions{
+			RU:        int(dynamo[0].RU.ValueInt64()),
+			NoScaling: boolPointer(dynamo[0].NoScaling),
+			NoBackup:  boolPointer(dynamo[0].NoBackup),
+		}
+	}
+
+	if len(cosmos) > 0 {
+		storage.Cosmos = &scalardb.CosmosOptions{
+			RU: int(cosmos[0].RU.ValueInt64()),
+		}
+	}
+
+	return storage
+}
+
+// checkBackendBlocks checks that the option blocks and options of a resource apply to the configured backend.
+func checkBackendBlocks(backend string, set []string, options map[string]string) error {
+	if backend == "" || backend == "multi-storage" {
+		return nil
+	}
+
+	for _, name := range set {
+		if name != backend {
+			return fmt.Errorf("the %s block cannot be used because the provider is configured for the %s backend", name, backend)
+		}
+	}
+
+	for _, key := range sortedKeys(options) {
+		option, ok := knownOptions[key]
+		if ok && !slices.Contains(option.backends, backend) {
+			return fmt.Errorf("options: %q is not supported by the %s backend", key, backend)
+		}
+	}
+
+	return nil
+}
+
+// withEffectiveStorageOptions returns the option blocks with the attributes that are not
+// configured set to the effective options, so that the state records what ScalarDB applies.
+func withEffectiveStorageOptions[C cassandraModel[C]](cassandra []C, dynamo []dynamoModel, cosmos []cosmosModel, options map[string]string) ([]C, []dynamoModel, []cosmosModel) {
+	for i := range cassandra {
+		cassandra[i] = cassandra[i].withEffectiveOptions(options)
+	}
+	for i := range dynamo {
+		dynamo[i].RU = effectiveInt64(dynamo[i].RU, options, "ru", 10)
+		dynamo[i].NoScaling = effectiveBool(dynamo[i].NoScaling, options, "no-scaling", false)
+		dynamo[i].NoBackup = effectiveBool(dynamo[i].NoBackup, options, "no-backup", false)
+	}
+	for i := range cosmos {
+		cosmos[i].RU = effectiveInt64(cosmos[i].RU, options, "ru", 400)
+	}
+	return cassandra, dynamo, cosmos
+}
+
+// effectiveString returns a string attribute, or if it is unknown because it is not
+// configured, the effective option or the default of ScalarDB.
+func effectiveString(v types.String, options map[string]string, key, defaultValue string) types.String {
+	if !v.IsUnknown() {
+		return v
+	}
+	if value, ok := options[key]; ok {
+		return types.StringValue(value)
+	}
+	return types.StringValue(defaultValue)
+}
+
+// effectiveInt64 returns an integer attribute, or if it is unknown because it is not
+// configured, the effective option or the default of ScalarDB.
+func effectiveInt64(v types.Int64, options map[string]string, key string, defaultValue int64) types.Int64 {
+	if !v.IsUnknown() {
+		return v
+	}
+	if n, err := strconv.ParseInt(options[key], 10, 64); err == nil {
+		return types.Int64Value(n)
+	}
+	return types.Int64Value(defaultValue)
+}
+
+// effectiveBool returns a bool attribute, or if it is unknown because it is not
+// configured, the effective option or the default of ScalarDB.
+func effectiveBool(v types.Bool, options map[string]string, key string, defaultValue bool) types.Bool {
+	if !v.IsUnknown() {
+		return v
+	}
+	if b, err := strconv.ParseBool(options[key]); err == nil {
+		return types.BoolValue(b)
+	}
+	return types.BoolValue(defaultValue)
+}
+
+// storageValues returns the attribute values of the dynamo and cosmos blocks.
+func storageValues(dynamo []dynamoModel, cosmos []cosmosModel) []attr.Value {
+	var values []attr.Value
+	for _, b := range dynamo {
+		values = append(values, b.RU, b.NoScaling, b.NoBackup)
+	}
+	for _, b := range cosmos {
+		values = append(values, b.RU)
+	}
+	return values
+}
+
+// dynamoBlockV0 returns the dynamo block in the SDKv2 provider.
+func dynamoBlockV0() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"ru":         schema.Int64Attribute{Optional: true},
+				"no_scaling": schema.BoolAttribute{Optional: true},
+				"no_backup":  schema.BoolAttribute{Optional: true},
+			},
+		},
+	}
+}
+
+// cosmosBlockV0 returns the cosmos block in the SDKv2 provider.
+func cosmosBlockV0() schema.ListNestedBlock {
+	return schema.ListNestedBlock{
+		NestedObject: schema.NestedBlockObject{
+			Attributes: map[string]schema.Attribute{
+				"ru": schema.Int64Attribute{Optional: true},
+			},
+		},
+	}
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"slices"
+	"sort"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+)
+
+var _ datasource.DataSourceWithConfigure = (*schemaExportDataSource)(nil)
+
+// schemaExportDataSource exports existing tables as Schema Loader JSON.
+type schemaExportDataSource struct {
+	meta *providerMeta
+}
+
+// schemaExportDataSourceModel is the state of the scalardb_schema_export data source.
+type schemaExportDataSourceModel struct {
+	ID                types.String `tfsdk:"id"`
+	Namespaces        types.Set    `tfsdk:"namespaces"`
+	ExcludeNamespaces types.Set    `tfsdk:"exclude_namespaces"`
+	Tables            types.List   `tfsdk:"tables"`
+	JSON              types.String `tfsdk:"json"`
+}
+
+func newSchemaExportDataSource() datasource.DataSource {
+	return &schemaExportDataSource{}
+}
+
+func (d *schemaExportDataSource) Metadata(ctx context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema_export"
+}
+
+func (d *schemaExportDataSource) Schema(ctx context.Context, req datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Exports existing ScalarDB tables in the Schema Loader JSON format.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "A hash of the exported JSON.",
+			},
+			"namespaces": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The namespaces to export. If not set, all namespaces are exported.",
+			},
+			"exclude_namespaces": schema.SetAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The namespaces to leave out of the export, e.g. the coordinator namespace.",
+			},
+			"tables": schema.ListAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The exported tables as namespace.table, in sorted order.",
+			},
+			"json": schema.StringAttribute{
+				Computed:    true,
+				Description: "The exported tables in the ScalarDB Schema Loader JSON format.",
+			},
+		},
+	}
+}
+
+func (d *schemaExportDataSource) Configure(ctx context.Context, req datasource.ConfigureRequest, resp *datasource.ConfigureResponse) {
+	d.meta = configureMeta(req.ProviderData, &resp.Diagnostics)
+}
+
+func (d *schemaExportDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	client := d.meta.client
+
+	var config schemaExportDataSourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespaces, err := client.GetNamespaceNames(ctx)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+
+	include := listStrings(config.Namespaces.Elements())
+	exclude := listStrings(config.ExcludeNamespaces.Elements())
+
+	var selected []string
+	for _, ns := range namespaces {
+		if len(include) > 0 && !slices.Contains(include, ns) {
+			continue
+		}
+		if slices.Contains(exclude, ns) {
+			continue
+		}
+		selected = append(selected, ns)
+	}
+	sort.Strings(selected)
+
+	var tables []*schemaLoaderTable
+	for _, ns := range selected {
+		tableNames, err := client.GetNamespaceTableNames(ctx, ns)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+			return
+		}
+		sort.Strings(tableNames)
+
+		for _, name := range tableNames {
+			def, err := client.GetTableSchema(ctx, ns, name)
+			if errors.Is(err, scalardb.ErrNotFound) {
+				// Dropped while exporting
+				continue
+			} else if err != nil {
+				resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+				return
+			}
+
+			tables = append(tables, &schemaLoaderTable{
+				Namespace:   ns,
+				Name:        name,
+				Transaction: true,
+				Definition:  def,
+			})
+		}
+	}
+
+	doc, err := renderSchemaLoaderJSON(tables)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to render the schema", err.Error())
+		return
+	}
+
+	keys := make([]string, 0, len(tables))
+	for _, table := range tables {
+		keys = append(keys, table.Key())
+	}
+
+	sum := sha256.Sum256([]byte(doc))
+	config.ID = types.StringValue(hex.EncodeToString(sum[:]))
+	config.Tables = stringListValue(keys)
+	config.JSON = types.StringValue(doc)
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, config)...)
+}
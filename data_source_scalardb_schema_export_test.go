@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+func TestAccSchemaExportDataSource(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+
+	createTable := func(namespace, table string) {
+		t.Helper()
+		_, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: namespace, IfNotExists: true})
+		testAccMust(t, err)
+		_, err = server.CreateTable(ctx, &pb.CreateTableRequest{
+			NamespaceName: namespace,
+			TableName:     table,
+			TableMetadata: &pb.TableMetadata{
+				Columns: map[string]pb.DataType{
+					"id":   pb.DataType_DATA_TYPE_INT,
+					"name": pb.DataType_DATA_TYPE_TEXT,
+				},
+				PartitionKeyColumnNames:   []string{"id"},
+				SecondaryIndexColumnNames: []string{"name"},
+			},
+		})
+		testAccMust(t, err)
+	}
+	createTable("app", "users")
+	createTable("coordinator", "state")
+
+	config := testAccProviderConfig(server, `
+data "scalardb_schema_export" "test" {
+  exclude_namespaces = ["coordinator"]
+}
+`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.scalardb_schema_export.test", "tables.#", "1"),
+					resource.TestCheckResourceAttr("data.scalardb_schema_export.test", "tables.0", "app.users"),
+					resource.TestCheckResourceAttrSet("data.scalardb_schema_export.test", "id"),
+					resource.TestCheckResourceAttrWith("data.scalardb_schema_export.test", "json", func(value string) error {
+						tables, err := parseSchemaLoaderJSON(value)
+						if err != nil {
+							return err
+						}
+						if len(tables["app.users"].Definition.SecondaryIndexes) != 1 {
+							return fmt.Errorf("app.users: got %+v", tables["app.users"].Definition)
+						}
+						return nil
+					}),
+				),
+			},
+			// Tables created outside of Terraform are picked up on the next read
+			{
+				PreConfig: func() { createTable("app", "orders") },
+				Config:    config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					resource.TestCheckResourceAttr("data.scalardb_schema_export.test", "tables.#", "2"),
+					resource.TestCheckResourceAttr("data.scalardb_schema_export.test", "tables.0", "app.orders"),
+					resource.TestCheckResourceAttr("data.scalardb_schema_export.test", "tables.1", "app.users"),
+				),
+			},
+		},
+	})
+}
@@ -15,10 +15,13 @@ provider "scalardb" {
 }
 
 resource "scalardb_namespace" "example" {
-  name               = "example_namespace"
-  replication_factor = 3
-  strategy_class     = "SimpleStrategy"
-  durable_writes     = true
+  name           = "example_namespace"
+  durable_writes = true
+
+  cassandra {
+    replication_strategy = "SimpleStrategy"
+    replication_factor   = 3
+  }
 }
 
 resource "scalardb_table" "users" {
@@ -52,7 +55,9 @@ resource "scalardb_table" "users" {
     type = "INT"
   }
 
-  compaction_strategy = "SizeTieredCompactionStrategy"
+  cassandra {
+    compaction_strategy = "STCS"
+  }
 
   clustering_order = {
     created_at = "DESC"
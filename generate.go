@@ -0,0 +1,306 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/hcl/v2"
+	"github.com/hashicorp/hcl/v2/hclwrite"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+	"github.com/zclconf/go-cty/cty"
+)
+
+// stringsFlag is a flag that can be given multiple times.
+type stringsFlag []string
+
+func (f *stringsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *stringsFlag) Set(value string) error {
+	*f = append(*f, value)
+	return nil
+}
+
+// runGenerate implements the generate command, which writes HCL and import blocks
+// for the namespaces and tables of an existing cluster.
+func runGenerate(ctx context.Context, args []string, stdout, stderr io.Writer) error {
+	flags := flag.NewFlagSet("generate", flag.ContinueOnError)
+	flags.SetOutput(stderr)
+	flags.Usage = func() {
+		fmt.Fprintln(stderr, "Usage: terraform-provider-scalardb generate [options]")
+		fmt.Fprintln(stderr)
+		fmt.Fprintln(stderr, "Writes scalardb_namespace and scalardb_table resources with matching import blocks")
+		fmt.Fprintln(stderr, "for the namespaces and tables of an existing ScalarDB cluster.")
+		fmt.Fprintln(stderr)
+		flags.PrintDefaults()
+	}
+
+	port := 60051
+	hopLimit := scalardb.DefaultHopLimit
+
+	host := flags.String("host", os.Getenv("SCALARDB_HOST"), "The host address of the ScalarDB server (env SCALARDB_HOST).")
+	flags.IntVar(&port, "port", port, "The port of the ScalarDB server (env SCALARDB_PORT).")
+	var endpoints stringsFlag
+	flags.Var(&endpoints, "endpoint", "The host or host:port of a ScalarDB Cluster node. Can be given multiple times instead of -host (env SCALARDB_ENDPOINTS).")
+	loadBalancing := flags.String("load-balancing", envOrDefault("SCALARDB_LOAD_BALANCING", scalardb.LoadBalancingRoundRobin),
+		"How requests are spread across the nodes: round_robin or pick_first (env SCALARDB_LOAD_BALANCING).")
+	username := flags.String("username", os.Getenv("SCALARDB_USERNAME"), "Username for ScalarDB authentication (env SCALARDB_USERNAME).")
+	password := flags.String("password", os.Getenv("SCALARDB_PASSWORD"), "Password for ScalarDB authentication (env SCALARDB_PASSWORD).")
+	flags.IntVar(&hopLimit, "hop-limit", hopLimit, "The maximum number of times a request can be forwarded between cluster nodes (env SCALARDB_HOP_LIMIT).")
+	var headerFlags stringsFlag
+	flags.Var(&headerFlags, "header", "A key=value gRPC metadata pair sent with every request. Can be given multiple times (env SCALARDB_HEADERS).")
+	output := flags.String("out", "", "The file to write to. Defaults to standard output.")
+	var namespaces, excludeNamespaces stringsFlag
+	flags.Var(&namespaces, "namespace", "A namespace to generate. Can be given multiple times. Defaults to all namespaces.")
+	flags.Var(&excludeNamespaces, "exclude-namespace", "A namespace to leave out. Can be given multiple times.")
+
+	if err := flags.Parse(args); err == flag.ErrHelp {
+		return nil
+	} else if err != nil {
+		return err
+	}
+	given := make(map[string]bool)
+	flags.Visit(func(f *flag.Flag) { given[f.Name] = true })
+	if !given["port"] {
+		if err := intFromEnv("SCALARDB_PORT", &port, "a port number"); err != nil {
+			return err
+		}
+	}
+	if !given["hop-limit"] {
+		if err := intFromEnv("SCALARDB_HOP_LIMIT", &hopLimit, "a positive integer"); err != nil {
+			return err
+		}
+	}
+	if len(endpoints) == 0 {
+		for _, endpoint := range strings.Split(os.Getenv("SCALARDB_ENDPOINTS"), ",") {
+			if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+				endpoints = append(endpoints, endpoint)
+			}
+		}
+	}
+	if *host == "" && len(endpoints) == 0 {
+		return fmt.Errorf("-host, -endpoint, SCALARDB_HOST or SCALARDB_ENDPOINTS is required")
+	}
+	if !slices.Contains(scalardb.LoadBalancingPolicies, *loadBalancing) {
+		return fmt.Errorf("-load-balancing must be one of %s, got %q", strings.Join(scalardb.LoadBalancingPolicies, ", "), *loadBalancing)
+	}
+	if port < 1 || port > 65535 {
+		name := "SCALARDB_PORT"
+		if given["port"] {
+			name = "-port"
+		}
+		return fmt.Errorf("%s must be a port number between 1 and 65535, got %d", name, port)
+	}
+	if hopLimit < 1 {
+		name := "SCALARDB_HOP_LIMIT"
+		if given["hop-limit"] {
+			name = "-hop-limit"
+		}
+		return fmt.Errorf("%s must be a positive integer, got %d", name, hopLimit)
+	}
+	headers, err := parseHeaders(os.Getenv("SCALARDB_HEADERS"))
+	if err != nil {
+		return fmt.Errorf("invalid SCALARDB_HEADERS: %w", err)
+	}
+	if len(headerFlags) > 0 {
+		headers = make(map[string]string)
+		for _, header := range headerFlags {
+			key, value, ok := strings.Cut(header, "=")
+			if !ok {
+				return fmt.Errorf("-header must be key=value, got %q", header)
+			}
+			headers[key] = value
+		}
+	}
+	for key, value := range headers {
+		if problem := checkHeader(key, value); problem != "" {
+			return fmt.Errorf("header %q %s", key, problem)
+		}
+	}
+
+	client := scalardb.NewClient(*host, port, *username, *password)
+	client.Endpoints = endpoints
+	client.LoadBalancing = *loadBalancing
+	client.HopLimit = hopLimit
+	client.Metadata = headers
+	client.UserAgent = userAgent(version, "", os.Getenv("SCALARDB_USER_AGENT_SUFFIX"))
+	defer client.Close()
+
+	src, err := generateHCL(ctx, client, namespaces, excludeNamespaces)
+	if err != nil {
+		return err
+	}
+
+	if *output == "" {
+		_, err = stdout.Write(src)
+		return err
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *output, err)
+	}
+	return nil
+}
+
+// generateHCL renders the namespaces and tables of the cluster as HCL with import blocks.
+// Namespaces, tables and columns are sorted so that the output is stable across runs.
+func generateHCL(ctx context.Context, client scalardb.AdminClient, include, exclude []string) ([]byte, error) {
+	namespaces, err := client.GetNamespaceNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(namespaces)
+
+	file := hclwrite.NewEmptyFile()
+	body := file.Body()
+	labels := make(map[string]bool)
+
+	for _, ns := range namespaces {
+		if len(include) > 0 && !slices.Contains(include, ns) {
+			continue
+		}
+		if slices.Contains(exclude, ns) {
+			continue
+		}
+
+		tableNames, err := client.GetNamespaceTableNames(ctx, ns)
+		if err != nil {
+			return nil, err
+		}
+		sort.Strings(tableNames)
+
+		nsLabel := uniqueLabel(labels, ns)
+		appendImportBlock(body, "scalardb_namespace", nsLabel, namespaceID(ns))
+
+		nsBlock := body.AppendNewBlock("resource", []string{"scalardb_namespace", nsLabel}).Body()
+		nsBlock.SetAttributeValue("name", cty.StringVal(ns))
+		body.AppendNewline()
+
+		for _, name := range tableNames {
+			table, err := client.GetTableSchema(ctx, ns, name)
+			if err != nil {
+				return nil, err
+			}
+
+			label := uniqueLabel(labels, ns+"_"+name)
+			appendImportBlock(body, "scalardb_table", label, tableID(ns, name))
+			appendTableBlock(body, label, nsLabel, name, table)
+			body.AppendNewline()
+		}
+	}
+
+	return hclwrite.Format(file.Bytes()), nil
+}
+
+// appendImportBlock appends an import block for a resource.
+func appendImportBlock(body *hclwrite.Body, resourceType, label, id string) {
+	block := body.AppendNewBlock("import", nil).Body()
+	block.SetAttributeTraversal("to", hcl.Traversal{
+		hcl.TraverseRoot{Name: resourceType},
+		hcl.TraverseAttr{Name: label},
+	})
+	block.SetAttributeValue("id", cty.StringVal(id))
+	body.AppendNewline()
+}
+
+// appendTableBlock appends a scalardb_table resource that references its namespace resource.
+func appendTableBlock(body *hclwrite.Body, label, nsLabel, name string, table *scalardb.TableDefinition) {
+	block := body.AppendNewBlock("resource", []string{"scalardb_table", label}).Body()
+	block.SetAttributeTraversal("namespace", hcl.Traversal{
+		hcl.TraverseRoot{Name: "scalardb_namespace"},
+		hcl.TraverseAttr{Name: nsLabel},
+		hcl.TraverseAttr{Name: "name"},
+	})
+	block.SetAttributeValue("name", cty.StringVal(name))
+	block.SetAttributeValue("partition_key", stringListVal(table.PartitionKey))
+	if len(table.ClusteringKey) > 0 {
+		block.SetAttributeValue("clustering_key", stringListVal(table.ClusteringKey))
+	}
+
+	descending := make(map[string]cty.Value)
+	for _, colName := range table.ClusteringKey {
+		if clusteringOrderOrDefault(table.ClusteringOrder, colName) == "DESC" {
+			descending[colName] = cty.StringVal("DESC")
+		}
+	}
+	if len(descending) > 0 {
+		block.SetAttributeValue("clustering_order", cty.MapVal(descending))
+	}
+
+	columns := append([]scalardb.ColumnDefinition{}, table.Columns...)
+	sort.Slice(columns, func(i, j int) bool {
+		return columns[i].Name < columns[j].Name
+	})
+	for _, column := range columns {
+		block.AppendNewline()
+		columnBlock := block.AppendNewBlock("column", nil).Body()
+		columnBlock.SetAttributeValue("name", cty.StringVal(column.Name))
+		columnBlock.SetAttributeValue("type", cty.StringVal(column.Type))
+		if slices.Contains(table.SecondaryIndexes, column.Name) {
+			columnBlock.SetAttributeValue("secondary_index", cty.True)
+		}
+		if column.Encrypted {
+			columnBlock.SetAttributeValue("encrypted", cty.True)
+		}
+	}
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^A-Za-z0-9_-]`)
+
+// uniqueLabel converts a name to a valid resource label that has not been used yet.
+func uniqueLabel(used map[string]bool, name string) string {
+	label := invalidLabelChars.ReplaceAllString(name, "_")
+	if label == "" || (label[0] >= '0' && label[0] <= '9') || label[0] == '-' {
+		label = "_" + label
+	}
+
+	unique := label
+	for i := 2; used[unique]; i++ {
+		unique = fmt.Sprintf("%s_%d", label, i)
+	}
+	used[unique] = true
+	return unique
+}
+
+// stringListVal converts a string slice to a cty list.
+func stringListVal(list []string) cty.Value {
+	if len(list) == 0 {
+		return cty.ListValEmpty(cty.String)
+	}
+	values := make([]cty.Value, 0, len(list))
+	for _, s := range list {
+		values = append(values, cty.StringVal(s))
+	}
+	return cty.ListVal(values)
+}
+
+// envOrDefault returns the value of an environment variable, or a default if it is unset.
+func envOrDefault(key, defaultValue string) string {
+	if v, ok := os.LookupEnv(key); ok {
+		return v
+	}
+	return defaultValue
+}
+
+// intFromEnv sets n to the value of an environment variable if it is set, and fails
+// naming the variable if the value is not an integer.
+func intFromEnv(key string, n *int, want string) error {
+	v, ok := os.LookupEnv(key)
+	if !ok {
+		return nil
+	}
+	value, err := strconv.Atoi(v)
+	if err != nil {
+		return fmt.Errorf("%s must be %s, got %q", key, want, v)
+	}
+	*n = value
+	return nil
+}
@@ -0,0 +1,33 @@
+package main
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestRunGenerateInvalidEnv(t *testing.T) {
+	tests := []struct {
+		env, value string
+		args       []string
+		wantErr    string
+	}{
+		{env: "SCALARDB_PORT", value: "abc", wantErr: `SCALARDB_PORT must be a port number, got "abc"`},
+		{env: "SCALARDB_PORT", value: "70000", wantErr: "SCALARDB_PORT must be a port number between 1 and 65535, got 70000"},
+		{env: "SCALARDB_PORT", value: "abc", args: []string{"-port", "0"}, wantErr: "-port must be a port number between 1 and 65535, got 0"},
+		{env: "SCALARDB_HOP_LIMIT", value: "ten", wantErr: `SCALARDB_HOP_LIMIT must be a positive integer, got "ten"`},
+		{env: "SCALARDB_HOP_LIMIT", value: "0", wantErr: "SCALARDB_HOP_LIMIT must be a positive integer, got 0"},
+		{env: "SCALARDB_HOP_LIMIT", value: "ten", args: []string{"-hop-limit", "0"}, wantErr: "-hop-limit must be a positive integer, got 0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.env+"="+tt.value, func(t *testing.T) {
+			t.Setenv("SCALARDB_HOST", "127.0.0.1")
+			t.Setenv(tt.env, tt.value)
+			err := runGenerate(context.Background(), tt.args, io.Discard, io.Discard)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("got error %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Resource IDs join the names that identify a resource with dots, e.g. namespace.table.
+// Dots and backslashes inside a name are escaped with a backslash, so that any name
+// round-trips and IDs of names without them look the same as before escaping was added.
+
+// formatID formats the names that identify a resource as an ID.
+func formatID(parts ...string) string {
+	escaped := make([]string, 0, len(parts))
+	for _, part := range parts {
+		part = strings.ReplaceAll(part, `\`, `\\`)
+		part = strings.ReplaceAll(part, ".", `\.`)
+		escaped = append(escaped, part)
+	}
+	return strings.Join(escaped, ".")
+}
+
+// parseID parses an ID formatted by formatID. format describes the expected parts,
+// e.g. "namespace.table", and its number of dots determines the number of parts.
+func parseID(id, format string) ([]string, error) {
+	n := strings.Count(format, ".") + 1
+
+	var parts []string
+	var part strings.Builder
+	for i := 0; i < len(id); i++ {
+		switch c := id[i]; c {
+		case '\\':
+			if i+1 == len(id) || (id[i+1] != '\\' && id[i+1] != '.') {
+				return nil, fmt.Errorf(`invalid ID %q: "\" must be followed by "." or "\" (expected %s)`, id, format)
+			}
+			i++
+			part.WriteByte(id[i])
+		case '.':
+			parts = append(parts, part.String())
+			part.Reset()
+		default:
+			part.WriteByte(c)
+		}
+	}
+	parts = append(parts, part.String())
+
+	if len(parts) != n {
+		return nil, fmt.Errorf(`invalid ID %q: expected %s, escaping "." and "\" in names with "\"`, id, format)
+	}
+	return parts, nil
+}
+
+// tableID returns the ID of a table.
+func tableID(namespace, name string) string {
+	return formatID(namespace, name)
+}
+
+// parseTableID returns the namespace and name of a table ID.
+func parseTableID(id string) (string, string, error) {
+	parts, err := parseID(id, "namespace.table")
+	if err != nil {
+		return "", "", err
+	}
+	return parts[0], parts[1], nil
+}
+
+// namespaceID returns the ID of a namespace.
+func namespaceID(name string) string {
+	return formatID(name)
+}
+
+// parseNamespaceID returns the name of a namespace ID.
+func parseNamespaceID(id string) (string, error) {
+	parts, err := parseID(id, "namespace")
+	if err != nil {
+		return "", err
+	}
+	return parts[0], nil
+}
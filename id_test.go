@@ -0,0 +1,121 @@
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+	"testing/quick"
+)
+
+// idName is a name for property tests that often contains the characters the ID codec escapes.
+type idName string
+
+func (idName) Generate(r *rand.Rand, size int) reflect.Value {
+	alphabet := []rune{'a', 'b', '_', '-', '.', '.', '\\', '\\', ' ', 'é', '名'}
+	name := make([]rune, r.Intn(size+1))
+	for i := range name {
+		name[i] = alphabet[r.Intn(len(alphabet))]
+	}
+	return reflect.ValueOf(idName(name))
+}
+
+func TestIDRoundTrip(t *testing.T) {
+	property := func(namespace, table idName) bool {
+		id := tableID(string(namespace), string(table))
+		gotNamespace, gotTable, err := parseTableID(id)
+		return err == nil && gotNamespace == string(namespace) && gotTable == string(table)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIDRoundTripArbitraryStrings(t *testing.T) {
+	property := func(parts []string) bool {
+		if len(parts) == 0 {
+			return true
+		}
+		format := strings.Repeat("part.", len(parts)-1) + "part"
+		got, err := parseID(formatID(parts...), format)
+		return err == nil && slices.Equal(got, parts)
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestNamespaceIDRoundTrip(t *testing.T) {
+	property := func(name idName) bool {
+		got, err := parseNamespaceID(namespaceID(string(name)))
+		return err == nil && got == string(name)
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 2000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIDInjective(t *testing.T) {
+	property := func(a1, b1, a2, b2 idName) bool {
+		if a1 == a2 && b1 == b2 {
+			return true
+		}
+		return tableID(string(a1), string(b1)) != tableID(string(a2), string(b2))
+	}
+	if err := quick.Check(property, &quick.Config{MaxCount: 5000}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestIDUnchangedForPlainNames(t *testing.T) {
+	// IDs of names without dots and backslashes are the same as before escaping was added
+	property := func(namespace, table idName) bool {
+		ns := strings.NewReplacer(".", "", `\`, "").Replace(string(namespace))
+		name := strings.NewReplacer(".", "", `\`, "").Replace(string(table))
+		return tableID(ns, name) == ns+"."+name && namespaceID(ns) == ns
+	}
+	if err := quick.Check(property, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestParseTableID(t *testing.T) {
+	tests := []struct {
+		id        string
+		namespace string
+		table     string
+		wantErr   bool
+	}{
+		{id: "ns.table", namespace: "ns", table: "table"},
+		{id: `my\.ns.table`, namespace: "my.ns", table: "table"},
+		{id: `ns.a\.b\.c`, namespace: "ns", table: "a.b.c"},
+		{id: `back\\slash.table`, namespace: `back\slash`, table: "table"},
+		{id: `ns.ends\\`, namespace: "ns", table: `ends\`},
+		{id: "ns", wantErr: true},
+		{id: "a.b.c", wantErr: true},
+		{id: `ns.table\`, wantErr: true},
+		{id: `ns.t\able`, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.id, func(t *testing.T) {
+			namespace, table, err := parseTableID(tt.id)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("expected an error, got %q, %q", namespace, table)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if namespace != tt.namespace || table != tt.table {
+				t.Errorf("got %q, %q, want %q, %q", namespace, table, tt.namespace, tt.table)
+			}
+			if got := tableID(namespace, table); got != tt.id {
+				t.Errorf("tableID = %q, want %q", got, tt.id)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// knownOption describes a well-known ScalarDB creation option.
+type knownOption struct {
+	// backends lists the storage backends that understand the option.
+	backends []string
+	// validate checks the option value and returns a description of the problem, if any.
+	validate func(value string) string
+}
+
+// knownOptions are the creation options that ScalarDB storage backends are known to accept.
+// Options not listed here are passed through to the server without validation.
+var knownOptions = map[string]knownOption{
+	"replication-strategy": {
+		backends: []string{"cassandra"},
+		validate: oneOf("SimpleStrategy", "NetworkTopologyStrategy"),
+	},
+	"replication-factor": {
+		backends: []string{"cassandra"},
+		validate: positiveInt,
+	},
+	"compaction-strategy": {
+		backends: []string{"cassandra"},
+		validate: oneOf("STCS", "LCS", "TWCS"),
+	},
+	"ru": {
+		backends: []string{"dynamo", "cosmos"},
+		validate: positiveInt,
+	},
+	"no-scaling": {
+		backends: []string{"dynamo"},
+		validate: isBool,
+	},
+	"no-backup": {
+		backends: []string{"dynamo"},
+		validate: isBool,
+	},
+}
+
+// optionsValidator validates the well-known keys of an options map.
+type optionsValidator struct{}
+
+var _ validator.Map = optionsValidator{}
+
+// optionsValidators returns the validators of an options attribute.
+func optionsValidators() []validator.Map {
+	return []validator.Map{optionsValidator{}}
+}
+
+func (v optionsValidator) Description(ctx context.Context) string {
+	return "well-known creation options must have valid values"
+}
+
+func (v optionsValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v optionsValidator) ValidateMap(ctx context.Context, req validator.MapRequest, resp *validator.MapResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+
+	elements := req.ConfigValue.Elements()
+	for _, key := range sortedKeys(elements) {
+		option, ok := knownOptions[key]
+		if !ok {
+			continue
+		}
+		element, ok := elements[key].(types.String)
+		if !ok || element.IsNull() || element.IsUnknown() {
+			continue
+		}
+		value := element.ValueString()
+		if problem := option.validate(value); problem != "" {
+			resp.Diagnostics.AddAttributeError(
+				req.Path.AtMapKey(key),
+				fmt.Sprintf("Invalid value for option %q", key),
+				fmt.Sprintf("The %s option (%s) %s, got %q.", key, strings.Join(option.backends, ", "), problem, value),
+			)
+		}
+	}
+}
+
+// expandOptions converts an options map to a string map. Unknown values are left out.
+func expandOptions(m types.Map) map[string]string {
+	options := make(map[string]string)
+	for key, value := range m.Elements() {
+		if s, ok := value.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			options[key] = s.ValueString()
+		}
+	}
+	return options
+}
+
+// mergeOptions merges option maps. Options in later maps take precedence.
+func mergeOptions(maps ...map[string]string) map[string]string {
+	options := make(map[string]string)
+	for _, m := range maps {
+		for key, value := range m {
+			options[key] = value
+		}
+	}
+	return options
+}
+
+// oneOf returns a validator that accepts only the given values.
+func oneOf(values ...string) func(string) string {
+	return func(value string) string {
+		for _, v := range values {
+			if value == v {
+				return ""
+			}
+		}
+		return fmt.Sprintf("must be one of %s", strings.Join(values, ", "))
+	}
+}
+
+// positiveInt accepts positive integers.
+func positiveInt(value string) string {
+	if n, err := strconv.Atoi(value); err != nil || n <= 0 {
+		return "must be a positive integer"
+	}
+	return ""
+}
+
+// isBool accepts "true" and "false".
+func isBool(value string) string {
+	if value != "true" && value != "false" {
+		return "must be true or false"
+	}
+	return ""
+}
@@ -0,0 +1,125 @@
+package main
+
+import (
+	"context"
+	"maps"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/setplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// requiresReplaceIfListChanged requires replacement when a list or list block changes.
+// Null and empty lists are treated as equal, since state written by the SDKv2 provider
+// recorded unset blocks as empty lists.
+func requiresReplaceIfListChanged() planmodifier.List {
+	return listplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.ListRequest, resp *listplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.PlanValue.IsUnknown() ||
+				len(req.PlanValue.Elements()) > 0 || len(req.StateValue.Elements()) > 0
+		},
+		"Changing the value replaces the resource. Null and empty values are treated as equal.",
+		"Changing the value replaces the resource. Null and empty values are treated as equal.",
+	)
+}
+
+// requiresReplaceIfMapChanged requires replacement when a map changes.
+// Null and empty maps are treated as equal.
+func requiresReplaceIfMapChanged() planmodifier.Map {
+	return mapplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+			resp.RequiresReplace = req.PlanValue.IsUnknown() ||
+				len(req.PlanValue.Elements()) > 0 || len(req.StateValue.Elements()) > 0
+		},
+		"Changing the value replaces the resource. Null and empty values are treated as equal.",
+		"Changing the value replaces the resource. Null and empty values are treated as equal.",
+	)
+}
+
+// requiresReplaceIfClusteringOrderChanged requires replacement when the order of a column
+// changes. Columns without an order are ascending, so an explicit ASC is equal to no order.
+// The state of imported tables only records descending orders.
+func requiresReplaceIfClusteringOrderChanged() planmodifier.Map {
+	return mapplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.MapRequest, resp *mapplanmodifier.RequiresReplaceIfFuncResponse) {
+			if req.PlanValue.IsUnknown() || !allKnown(slices.Collect(maps.Values(req.PlanValue.Elements()))...) {
+				resp.RequiresReplace = true
+				return
+			}
+			resp.RequiresReplace = !maps.Equal(descendingOrders(req.PlanValue), descendingOrders(req.StateValue))
+		},
+		"Changing the order of a column replaces the resource. ASC is equal to no order.",
+		"Changing the order of a column replaces the resource. `ASC` is equal to no order.",
+	)
+}
+
+// descendingOrders returns the clustering orders other than ASC.
+func descendingOrders(v types.Map) map[string]string {
+	orders := expandOptions(v)
+	maps.DeleteFunc(orders, func(colName, order string) bool { return order == "ASC" })
+	return orders
+}
+
+// requiresReplaceIfColumnsChanged requires replacement when columns are added, removed or
+// changed in any way other than their secondary_index, which is changed in place.
+// The configured columns are compared rather than the planned ones, since the defaults of
+// the nested attributes are not reliably applied to set elements when this runs.
+func requiresReplaceIfColumnsChanged() planmodifier.Set {
+	return setplanmodifier.RequiresReplaceIf(
+		func(ctx context.Context, req planmodifier.SetRequest, resp *setplanmodifier.RequiresReplaceIfFuncResponse) {
+			configColumns, ok := columnKeys(req.ConfigValue)
+			if !ok {
+				resp.RequiresReplace = true
+				return
+			}
+			stateColumns, _ := columnKeys(req.StateValue)
+			resp.RequiresReplace = !slices.Equal(configColumns, stateColumns)
+		},
+		"Changing a column other than its secondary_index replaces the resource.",
+		"Changing a column other than its `secondary_index` replaces the resource.",
+	)
+}
+
+// columnKeys returns the sorted name, type and encryption of each column. A null encrypted
+// is treated as false. It returns false if any of them is unknown.
+func columnKeys(columns types.Set) ([]string, bool) {
+	if columns.IsUnknown() {
+		return nil, false
+	}
+	keys := make([]string, 0, len(columns.Elements()))
+	for _, element := range columns.Elements() {
+		object, ok := element.(types.Object)
+		if !ok || object.IsUnknown() {
+			return nil, false
+		}
+		attributes := object.Attributes()
+		name, _ := attributes["name"].(types.String)
+		columnType, _ := attributes["type"].(types.String)
+		encrypted, _ := attributes["encrypted"].(types.Bool)
+		if name.IsUnknown() || columnType.IsUnknown() || encrypted.IsUnknown() {
+			return nil, false
+		}
+		keys = append(keys, strings.Join([]string{
+			name.ValueString(),
+			columnType.ValueString(),
+			strconv.FormatBool(encrypted.ValueBool()),
+		}, "\x00"))
+	}
+	sort.Strings(keys)
+	return keys, true
+}
+
+// planMetadataInSync plans an in-place update that repairs the metadata when Read
+// found it out of sync and repair_on_drift is enabled.
+func planMetadataInSync(repairOnDrift, inSync types.Bool) types.Bool {
+	if repairOnDrift.ValueBool() && !inSync.IsNull() && !inSync.IsUnknown() && !inSync.ValueBool() {
+		return types.BoolValue(true)
+	}
+	return inSync
+}
@@ -2,60 +2,404 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework-validators/int64validator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/listvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/provider/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
 )
 
-// Provider returns a terraform.ResourceProvider.
-func Provider() *schema.Provider {
-	return &schema.Provider{
-		Schema: map[string]*schema.Schema{
-			"host": {
-				Type:        schema.TypeString,
-				Required:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SCALARDB_HOST", nil),
-				Description: "The host address of the ScalarDB server.",
+var _ provider.Provider = (*scalarDBProvider)(nil)
+
+// scalarDBProvider is the ScalarDB provider.
+type scalarDBProvider struct {
+	version string
+}
+
+// providerModel is the provider configuration.
+type providerModel struct {
+	Host                    types.String                   `tfsdk:"host"`
+	Port                    types.Int64                    `tfsdk:"port"`
+	Endpoints               types.List                     `tfsdk:"endpoints"`
+	LoadBalancing           types.String                   `tfsdk:"load_balancing"`
+	Backend                 types.String                   `tfsdk:"backend"`
+	Username                types.String                   `tfsdk:"username"`
+	Password                types.String                   `tfsdk:"password"`
+	HopLimit                types.Int64                    `tfsdk:"hop_limit"`
+	Headers                 types.Map                      `tfsdk:"headers"`
+	UserAgentSuffix         types.String                   `tfsdk:"user_agent_suffix"`
+	DefaultNamespaceOptions []defaultNamespaceOptionsModel `tfsdk:"default_namespace_options"`
+	DefaultTableOptions     []defaultTableOptionsModel     `tfsdk:"default_table_options"`
+}
+
+// New returns a function that creates the provider.
+func New(version string) func() provider.Provider {
+	return func() provider.Provider {
+		return &scalarDBProvider{version: version}
+	}
+}
+
+func (p *scalarDBProvider) Metadata(ctx context.Context, req provider.MetadataRequest, resp *provider.MetadataResponse) {
+	resp.TypeName = "scalardb"
+	resp.Version = p.version
+}
+
+func (p *scalarDBProvider) Schema(ctx context.Context, req provider.SchemaRequest, resp *provider.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"host": schema.StringAttribute{
+				Optional:    true,
+				Description: "The host address of the ScalarDB server. If it resolves to several addresses, requests are balanced across them. Required unless endpoints is set. Can also be set with the SCALARDB_HOST environment variable.",
 			},
-			"port": {
-				Type:        schema.TypeInt,
+			"port": schema.Int64Attribute{
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SCALARDB_PORT", 60051),
-				Description: "The port of the ScalarDB server.",
+				Description: "The port of the ScalarDB server, and of endpoints without a port. Can also be set with the SCALARDB_PORT environment variable. Defaults to 60051.",
+				Validators: []validator.Int64{
+					int64validator.Between(1, 65535),
+				},
 			},
-			"username": {
-				Type:        schema.TypeString,
+			"endpoints": schema.ListAttribute{
+				ElementType: types.StringType,
 				Optional:    true,
-				DefaultFunc: schema.EnvDefaultFunc("SCALARDB_USERNAME", nil),
-				Description: "Username for ScalarDB authentication.",
+				Description: "The addresses of the ScalarDB Cluster nodes, as host or host:port. Requests are balanced across the nodes and fail over when one is lost. Conflicts with host. Can also be set with the SCALARDB_ENDPOINTS environment variable as a comma-separated list.",
+				Validators: []validator.List{
+					listvalidator.SizeAtLeast(1),
+					listvalidator.ConflictsWith(path.MatchRoot("host")),
+				},
 			},
-			"password": {
-				Type:        schema.TypeString,
+			"load_balancing": schema.StringAttribute{
+				Optional:    true,
+				Description: "How requests are spread across the nodes: round_robin sends them to all healthy nodes in turn, and pick_first to one node until it fails. Can also be set with the SCALARDB_LOAD_BALANCING environment variable. Defaults to round_robin.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(scalardb.LoadBalancingPolicies...),
+				},
+			},
+			"backend": schema.StringAttribute{
+				Optional:    true,
+				Description: "The storage backend of the ScalarDB cluster. If set, storage-specific option blocks for other backends are rejected. Can also be set with the SCALARDB_BACKEND environment variable.",
+				Validators: []validator.String{
+					stringvalidator.OneOf(backends...),
+				},
+			},
+			"username": schema.StringAttribute{
+				Optional:    true,
+				Description: "Username for ScalarDB authentication. Can also be set with the SCALARDB_USERNAME environment variable.",
+			},
+			"password": schema.StringAttribute{
 				Optional:    true,
 				Sensitive:   true,
-				DefaultFunc: schema.EnvDefaultFunc("SCALARDB_PASSWORD", nil),
-				Description: "Password for ScalarDB authentication.",
+				Description: "Password for ScalarDB authentication. Can also be set with the SCALARDB_PASSWORD environment variable.",
+			},
+			"hop_limit": schema.Int64Attribute{
+				Optional:    true,
+				Description: "The maximum number of times a request can be forwarded between cluster nodes. Can also be set with the SCALARDB_HOP_LIMIT environment variable. Defaults to 10.",
+				Validators: []validator.Int64{
+					int64validator.AtLeast(1),
+				},
+			},
+			"headers": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "gRPC metadata sent with every request, e.g. for routing in a gateway. Can also be set with the SCALARDB_HEADERS environment variable as comma-separated key=value pairs.",
+			},
+			"user_agent_suffix": schema.StringAttribute{
+				Optional:    true,
+				Description: "Appended to the user agent, which names the provider and Terraform versions. Can also be set with the SCALARDB_USER_AGENT_SUFFIX environment variable.",
 			},
 		},
-		ResourcesMap: map[string]*schema.Resource{
-			"scalardb_namespace": resourceScalarDBNamespace(),
-			"scalardb_table":     resourceScalarDBTable(),
+		Blocks: map[string]schema.Block{
+			"default_namespace_options": defaultOptionsBlock(
+				"Default creation options for all namespaces. Resource-level options take precedence.",
+				providerCassandraNamespaceBlock(),
+			),
+			"default_table_options": defaultOptionsBlock(
+				"Default creation options for all tables. Resource-level options take precedence.",
+				providerCassandraTableBlock(),
+			),
 		},
-		ConfigureContextFunc: providerConfigure,
 	}
 }
 
-// providerConfigure configures the provider and returns a client.
-func providerConfigure(ctx context.Context, d *schema.ResourceData) (interface{}, diag.Diagnostics) {
-	host := d.Get("host").(string)
-	port := d.Get("port").(int)
-	username := d.Get("username").(string)
-	password := d.Get("password").(string)
+func (p *scalarDBProvider) Resources(ctx context.Context) []func() resource.Resource {
+	return []func() resource.Resource{
+		newNamespaceResource,
+		newTableResource,
+		newSchemaResource,
+	}
+}
+
+func (p *scalarDBProvider) DataSources(ctx context.Context) []func() datasource.DataSource {
+	return []func() datasource.DataSource{
+		newSchemaExportDataSource,
+	}
+}
+
+// providerMeta is the configured provider passed to resources.
+type providerMeta struct {
+	client  scalardb.AdminClient
+	backend string
+	// defaultNamespaceOptions and defaultTableOptions are merged under the options of each resource.
+	defaultNamespaceOptions map[string]string
+	defaultTableOptions     map[string]string
+}
+
+// Configure configures the provider and creates a client.
+func (p *scalarDBProvider) Configure(ctx context.Context, req provider.ConfigureRequest, resp *provider.ConfigureResponse) {
+	var config providerModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	var endpoints []string
+	if config.Endpoints.IsNull() {
+		if v, ok := os.LookupEnv("SCALARDB_ENDPOINTS"); ok {
+			for _, endpoint := range strings.Split(v, ",") {
+				if endpoint = strings.TrimSpace(endpoint); endpoint != "" {
+					endpoints = append(endpoints, endpoint)
+				}
+			}
+		}
+	}
+	// The host or endpoints may be unknown during plan, e.g. when they come from the output
+	// of another resource. They are only required once they are known.
+	addressUnknown := config.Host.IsUnknown() || config.Endpoints.IsUnknown()
+	for _, value := range config.Endpoints.Elements() {
+		if value.IsUnknown() {
+			addressUnknown = true
+		} else if s, ok := value.(types.String); ok && !s.IsNull() {
+			endpoints = append(endpoints, s.ValueString())
+		}
+	}
+
+	host := stringValueOrEnv(config.Host, "SCALARDB_HOST", "")
+	if host == "" && len(endpoints) == 0 && !addressUnknown {
+		resp.Diagnostics.AddAttributeError(path.Root("host"), "Missing ScalarDB host",
+			"Set host or endpoints in the provider configuration, or the SCALARDB_HOST or SCALARDB_ENDPOINTS environment variable.")
+	}
+
+	port := 60051
+	if !config.Port.IsNull() && !config.Port.IsUnknown() {
+		port = int(config.Port.ValueInt64())
+		if port < 1 || port > 65535 {
+			resp.Diagnostics.AddAttributeError(path.Root("port"), "Invalid ScalarDB port",
+				fmt.Sprintf("The port must be a number between 1 and 65535, got %d.", port))
+		}
+	} else if v, ok := os.LookupEnv("SCALARDB_PORT"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 || n > 65535 {
+			resp.Diagnostics.AddAttributeError(path.Root("port"), "Invalid SCALARDB_PORT",
+				fmt.Sprintf("SCALARDB_PORT must be a port number between 1 and 65535, got %q.", v))
+		}
+		port = n
+	}
+
+	for i, endpoint := range endpoints {
+		if _, err := scalardb.EndpointAddress(endpoint, port); err != nil {
+			resp.Diagnostics.AddAttributeError(path.Root("endpoints").AtListIndex(i), "Invalid ScalarDB endpoint", err.Error())
+		}
+	}
+
+	loadBalancing := stringValueOrEnv(config.LoadBalancing, "SCALARDB_LOAD_BALANCING", scalardb.LoadBalancingRoundRobin)
+	if !slices.Contains(scalardb.LoadBalancingPolicies, loadBalancing) {
+		resp.Diagnostics.AddAttributeError(path.Root("load_balancing"), "Invalid SCALARDB_LOAD_BALANCING",
+			fmt.Sprintf("SCALARDB_LOAD_BALANCING must be one of %s, got %q.", strings.Join(scalardb.LoadBalancingPolicies, ", "), loadBalancing))
+	}
+
+	backend := stringValueOrEnv(config.Backend, "SCALARDB_BACKEND", "")
+	username := stringValueOrEnv(config.Username, "SCALARDB_USERNAME", "")
+	password := stringValueOrEnv(config.Password, "SCALARDB_PASSWORD", "")
+
+	hopLimit := scalardb.DefaultHopLimit
+	if !config.HopLimit.IsNull() && !config.HopLimit.IsUnknown() {
+		hopLimit = int(config.HopLimit.ValueInt64())
+	} else if v, ok := os.LookupEnv("SCALARDB_HOP_LIMIT"); ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			resp.Diagnostics.AddAttributeError(path.Root("hop_limit"), "Invalid SCALARDB_HOP_LIMIT",
+				fmt.Sprintf("SCALARDB_HOP_LIMIT must be a positive integer, got %q.", v))
+		}
+		hopLimit = n
+	}
+
+	headers := expandOptions(config.Headers)
+	if config.Headers.IsNull() {
+		if v, ok := os.LookupEnv("SCALARDB_HEADERS"); ok {
+			var err error
+			if headers, err = parseHeaders(v); err != nil {
+				resp.Diagnostics.AddAttributeError(path.Root("headers"), "Invalid SCALARDB_HEADERS", err.Error())
+			}
+		}
+	}
+	for key, value := range headers {
+		if problem := checkHeader(key, value); problem != "" {
+			resp.Diagnostics.AddAttributeError(path.Root("headers").AtMapKey(key), fmt.Sprintf("Invalid header %q", key),
+				fmt.Sprintf("The header %q %s.", key, problem))
+		}
+	}
+
+	userAgentSuffix := stringValueOrEnv(config.UserAgentSuffix, "SCALARDB_USER_AGENT_SUFFIX", "")
+
+	defaultNamespaceOptions, err := expandDefaultNamespaceOptions(config.DefaultNamespaceOptions, backend)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("default_namespace_options"), "Invalid default namespace options", err.Error())
+	}
+
+	defaultTableOptions, err := expandDefaultTableOptions(config.DefaultTableOptions, backend)
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("default_table_options"), "Invalid default table options", err.Error())
+	}
+
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	client := scalardb.NewClient(host, port, username, password)
+	client.Endpoints = endpoints
+	client.LoadBalancing = loadBalancing
+	client.HopLimit = hopLimit
+	client.Metadata = headers
+	client.UserAgent = userAgent(p.version, req.TerraformVersion, userAgentSuffix)
+
+	meta := &providerMeta{
+		client:                  client,
+		backend:                 backend,
+		defaultNamespaceOptions: defaultNamespaceOptions,
+		defaultTableOptions:     defaultTableOptions,
+	}
+	resp.ResourceData = meta
+	resp.DataSourceData = meta
+}
+
+// stringValueOrEnv returns the configured value, or the value of an environment variable
+// if the attribute is not set.
+func stringValueOrEnv(v types.String, env, defaultValue string) string {
+	if !v.IsNull() && !v.IsUnknown() {
+		return v.ValueString()
+	}
+	if value, ok := os.LookupEnv(env); ok {
+		return value
+	}
+	return defaultValue
+}
 
-	// Warning or errors can be collected in a slice type
+// userAgent returns the user agent of the provider.
+func userAgent(providerVersion, terraformVersion, suffix string) string {
+	ua := "terraform-provider-scalardb/" + providerVersion
+	if terraformVersion != "" {
+		ua += " Terraform/" + terraformVersion
+	}
+	if suffix != "" {
+		ua += " " + suffix
+	}
+	return ua
+}
+
+// parseHeaders parses comma-separated key=value pairs.
+func parseHeaders(s string) (map[string]string, error) {
+	headers := make(map[string]string)
+	for _, pair := range strings.Split(s, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(pair, "=")
+		if !ok {
+			return nil, fmt.Errorf("expected comma-separated key=value pairs, got %q", pair)
+		}
+		headers[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return headers, nil
+}
+
+// reservedHeaders are set by gRPC itself.
+var reservedHeaders = []string{"content-type", "te"}
+
+// checkHeader returns why a header cannot be sent as gRPC metadata, or "" if it can.
+func checkHeader(key, value string) string {
+	if key == "" {
+		return "must not be empty"
+	}
+	lower := strings.ToLower(key)
+	for _, r := range lower {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+			return "may contain only letters, digits, '-', '_' and '.'"
+		}
+	}
+	if lower == "user-agent" {
+		return "is set by the provider; use user_agent_suffix to extend it"
+	}
+	if strings.HasPrefix(lower, "grpc-") || slices.Contains(reservedHeaders, lower) {
+		return "is reserved by gRPC"
+	}
+	if !strings.HasSuffix(lower, "-bin") {
+		for _, r := range value {
+			if r < 0x20 || r > 0x7e {
+				return "must have a value of printable ASCII characters"
+			}
+		}
+	}
+	return ""
+}
+
+// configureMeta extracts the provider meta passed to Configure of a resource or data source.
+// It returns nil if the provider has not been configured yet.
+func configureMeta(providerData any, diags *diag.Diagnostics) *providerMeta {
+	if providerData == nil {
+		return nil
+	}
+	meta, ok := providerData.(*providerMeta)
+	if !ok {
+		diags.AddError("Unexpected provider data",
+			fmt.Sprintf("Expected *providerMeta, got %T. Please report this issue to the provider developers.", providerData))
+		return nil
+	}
+	return meta
+}
+
+// clientErrorDiagnostics converts an error returned by the ScalarDB client to diagnostics.
+// Permission errors name the privilege the failed operation requires.
+func clientErrorDiagnostics(err error) diag.Diagnostics {
 	var diags diag.Diagnostics
 
-	client := NewClient(host, port, username, password)
+	var scalarDBErr *scalardb.Error
+	if !errors.As(err, &scalarDBErr) {
+		diags.AddError(err.Error(), "")
+		return diags
+	}
+
+	switch {
+	case errors.Is(err, scalardb.ErrPermissionDenied):
+		detail := "The ScalarDB user configured for the provider lacks a privilege required for this operation."
+		if scalarDBErr.Privilege != "" {
+			detail = fmt.Sprintf("The ScalarDB user configured for the provider needs the %s privilege to %s.",
+				scalarDBErr.Privilege, scalarDBErr.Op)
+		}
+		diags.AddError(
+			fmt.Sprintf("Permission denied: failed to %s", scalarDBErr.Op),
+			fmt.Sprintf("%s\n\n%s", detail, scalarDBErr.Message),
+		)
+	case errors.Is(err, scalardb.ErrUnavailable):
+		diags.AddError(
+			fmt.Sprintf("ScalarDB is unavailable: failed to %s", scalarDBErr.Op),
+			fmt.Sprintf("Check that the ScalarDB Cluster is reachable at the configured host and port.\n\n%s", scalarDBErr.Message),
+		)
+	default:
+		diags.AddError(err.Error(), "")
+	}
 
-	return client, diags
+	return diags
 }
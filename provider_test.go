@@ -0,0 +1,395 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/provider"
+	"github.com/hashicorp/terraform-plugin-framework/providerserver"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tfprotov6"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+)
+
+// testAccProtoV6ProviderFactories serves the provider in-process for acceptance tests.
+var testAccProtoV6ProviderFactories = map[string]func() (tfprotov6.ProviderServer, error){
+	"scalardb": providerserver.NewProtocol6WithError(New("test")()),
+}
+
+// testAccMockServer starts a mock ScalarDB cluster on an ephemeral port for the duration
+// of an acceptance test.
+func testAccMockServer(t *testing.T, opts ...mock.Option) *mock.Instance {
+	t.Helper()
+	server, err := mock.Listen("127.0.0.1:0", append([]mock.Option{mock.WithLogger(nil)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(server.Stop)
+	return server
+}
+
+// testAccProviderConfig returns a provider block that connects to a mock server,
+// followed by the given configuration.
+func testAccProviderConfig(server *mock.Instance, config string) string {
+	return fmt.Sprintf(`
+provider "scalardb" {
+  host = "127.0.0.1"
+  port = %d
+}
+`, server.Port()) + config
+}
+
+// testAccCheckNamespaceExists checks whether a namespace exists in a mock server.
+func testAccCheckNamespaceExists(server *mock.Instance, namespace string, want bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		resp, err := server.NamespaceExists(context.Background(), &pb.NamespaceExistsRequest{NamespaceName: namespace})
+		if err != nil {
+			return err
+		}
+		if resp.Exists != want {
+			return fmt.Errorf("namespace %s exists: got %t, want %t", namespace, resp.Exists, want)
+		}
+		return nil
+	}
+}
+
+// testAccCheckTableExists checks whether a table exists in a mock server.
+func testAccCheckTableExists(server *mock.Instance, namespace, table string, want bool) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		resp, err := server.TableExists(context.Background(), &pb.TableExistsRequest{NamespaceName: namespace, TableName: table})
+		if err != nil {
+			return err
+		}
+		if resp.Exists != want {
+			return fmt.Errorf("table %s.%s exists: got %t, want %t", namespace, table, resp.Exists, want)
+		}
+		return nil
+	}
+}
+
+// testAccCheckTableMetadata checks the metadata of a table in a mock server.
+func testAccCheckTableMetadata(server *mock.Instance, namespace, table string, check func(*pb.TableMetadata) error) func(*terraform.State) error {
+	return func(*terraform.State) error {
+		resp, err := server.GetTableMetadata(context.Background(), &pb.GetTableMetadataRequest{NamespaceName: namespace, TableName: table})
+		if err != nil {
+			return err
+		}
+		return check(resp.TableMetadata)
+	}
+}
+
+// testAccMust fails the test if an out-of-band change to a mock server fails.
+func testAccMust(t *testing.T, err error) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// fakeAdminClient is an AdminClient that serves namespaces and table metadata from memory.
+// Methods that are not overridden panic.
+type fakeAdminClient struct {
+	scalardb.AdminClient
+	// namespaces are the namespaces that exist, and whether ScalarDB lists them.
+	namespaces map[string]bool
+	tables     map[string]*scalardb.TableDefinition
+	err        error
+}
+
+func (c *fakeAdminClient) NamespaceExists(ctx context.Context, name string) (bool, error) {
+	_, ok := c.namespaces[name]
+	return ok, c.err
+}
+
+func (c *fakeAdminClient) GetNamespaceNames(ctx context.Context) ([]string, error) {
+	var names []string
+	for name, listed := range c.namespaces {
+		if listed {
+			names = append(names, name)
+		}
+	}
+	return names, c.err
+}
+
+func (c *fakeAdminClient) GetNamespaceTableNames(ctx context.Context, namespace string) ([]string, error) {
+	var names []string
+	for key := range c.tables {
+		if ns, name, _ := strings.Cut(key, "."); ns == namespace {
+			names = append(names, name)
+		}
+	}
+	return names, c.err
+}
+
+func (c *fakeAdminClient) TableExists(ctx context.Context, namespace, name string) (bool, error) {
+	_, ok := c.tables[namespace+"."+name]
+	return ok, c.err
+}
+
+func (c *fakeAdminClient) GetTableSchema(ctx context.Context, namespace, name string) (*scalardb.TableDefinition, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	table, ok := c.tables[namespace+"."+name]
+	if !ok {
+		return nil, fmt.Errorf("failed to get table metadata: %w", scalardb.ErrNotFound)
+	}
+	return table, nil
+}
+
+// upgradeTestState runs the state upgrader of a resource for the given prior version
+// on a state fixture in testdata/state and returns the upgraded state.
+func upgradeTestState(t *testing.T, r resource.ResourceWithUpgradeState, version int64, fixture string) (tfsdk.State, []string) {
+	t.Helper()
+	ctx := context.Background()
+
+	upgrader, ok := r.UpgradeState(ctx)[version]
+	if !ok {
+		t.Fatalf("no state upgrader for version %d", version)
+	}
+
+	raw, err := os.ReadFile(filepath.Join("testdata", "state", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	priorType := upgrader.PriorSchema.Type().TerraformType(ctx)
+	prior, err := (&tfprotov6.RawState{JSON: raw}).UnmarshalWithOpts(priorType, tfprotov6.UnmarshalOpts{
+		ValueFromJSONOpts: tftypes.ValueFromJSONOpts{IgnoreUndefinedAttributes: true},
+	})
+	if err != nil {
+		t.Fatalf("failed to decode %s: %s", fixture, err)
+	}
+
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
+	currentType := schemaResp.Schema.Type().TerraformType(ctx)
+
+	req := resource.UpgradeStateRequest{
+		State: &tfsdk.State{Raw: prior, Schema: *upgrader.PriorSchema},
+	}
+	resp := resource.UpgradeStateResponse{
+		State: tfsdk.State{Raw: tftypes.NewValue(currentType, nil), Schema: schemaResp.Schema},
+	}
+	upgrader.StateUpgrader(ctx, req, &resp)
+
+	var warnings []string
+	for _, d := range resp.Diagnostics {
+		if d.Severity() == diag.SeverityError {
+			t.Fatalf("%s: %s", d.Summary(), d.Detail())
+		}
+		warnings = append(warnings, d.Summary())
+	}
+	return resp.State, warnings
+}
+
+func TestParseHeaders(t *testing.T) {
+	got, err := parseHeaders("x-route=blue, x-team = data,,x-empty=")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{"x-route": "blue", "x-team": "data", "x-empty": ""}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+	if _, err := parseHeaders("x-route"); err == nil {
+		t.Error("a pair without = was accepted")
+	}
+}
+
+func TestCheckHeader(t *testing.T) {
+	tests := []struct {
+		key, value string
+		valid      bool
+	}{
+		{key: "x-route", value: "blue", valid: true},
+		{key: "X-Team", value: "data", valid: true},
+		{key: "x-trace-bin", value: "\x00\x01", valid: true},
+		{key: "", value: "blue"},
+		{key: "x route", value: "blue"},
+		{key: "grpc-timeout", value: "1S"},
+		{key: "content-type", value: "application/grpc"},
+		{key: "user-agent", value: "curl"},
+		{key: "x-route", value: "bl\nue"},
+	}
+	for _, tt := range tests {
+		if problem := checkHeader(tt.key, tt.value); (problem == "") != tt.valid {
+			t.Errorf("checkHeader(%q, %q) = %q, want valid %t", tt.key, tt.value, problem, tt.valid)
+		}
+	}
+}
+
+func TestUserAgent(t *testing.T) {
+	if got, want := userAgent("1.2.0", "1.9.0", "team-data/1.0"), "terraform-provider-scalardb/1.2.0 Terraform/1.9.0 team-data/1.0"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+	if got, want := userAgent("dev", "", ""), "terraform-provider-scalardb/dev"; got != want {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestProviderDefaultOptions(t *testing.T) {
+	ctx := context.Background()
+	defaultNamespaceOptions, err := expandDefaultNamespaceOptions([]defaultNamespaceOptionsModel{{
+		Cassandra: []cassandraNamespaceModel{{ReplicationStrategy: types.StringNull(), ReplicationFactor: types.Int64Value(1)}},
+		Options:   stringMapValue(map[string]string{"durable_writes": "false"}),
+	}}, "cassandra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defaultTableOptions, err := expandDefaultTableOptions([]defaultTableOptionsModel{{
+		Cassandra: []cassandraTableModel{{CompactionStrategy: types.StringValue("TWCS")}},
+		Options:   stringMapValue(map[string]string{"replication-factor": "1"}),
+	}}, "cassandra")
+	if err != nil {
+		t.Fatal(err)
+	}
+	meta := &providerMeta{defaultNamespaceOptions: defaultNamespaceOptions, defaultTableOptions: defaultTableOptions}
+
+	// Attributes that are not configured are unknown in the plan of a new resource.
+	namespace := namespaceResourceModel{
+		DurableWrites: types.BoolUnknown(),
+		Cassandra:     []cassandraNamespaceModel{{ReplicationStrategy: types.StringValue("NetworkTopologyStrategy"), ReplicationFactor: types.Int64Unknown()}},
+		Options:       types.MapNull(types.StringType),
+	}
+	namespaceOptions := expandNamespaceOptions(&namespace, meta).Map()
+	want := map[string]string{"replication-strategy": "NetworkTopologyStrategy", "replication-factor": "1", "durable_writes": "false"}
+	if !maps.Equal(namespaceOptions, want) {
+		t.Errorf("namespace options: got %v, want %v", namespaceOptions, want)
+	}
+	namespace.setEffectiveOptions(namespaceOptions)
+	if got := namespace.Cassandra[0].ReplicationFactor; !got.Equal(types.Int64Value(1)) {
+		t.Errorf("replication_factor: got %s, want 1", got)
+	}
+	if got := namespace.DurableWrites; !got.Equal(types.BoolValue(false)) {
+		t.Errorf("durable_writes: got %s, want false", got)
+	}
+
+	table := tableResourceModel{
+		Column:          types.SetNull(types.ObjectType{AttrTypes: columnAttrTypes}),
+		PartitionKey:    types.ListNull(types.StringType),
+		ClusteringKey:   types.ListNull(types.StringType),
+		ClusteringOrder: types.MapNull(types.StringType),
+		Cassandra:       []cassandraTableModel{{CompactionStrategy: types.StringValue("LCS")}},
+		Options:         types.MapNull(types.StringType),
+	}
+	tableOptions := expandTableDefinition(ctx, &table, meta).CreationOptions()
+	want = map[string]string{"compaction-strategy": "LCS", "replication-factor": "1"}
+	if !maps.Equal(tableOptions, want) {
+		t.Errorf("table options: got %v, want %v", tableOptions, want)
+	}
+
+	// Attributes without an effective option get the defaults of ScalarDB.
+	dynamo := []dynamoModel{{RU: types.Int64Unknown(), NoScaling: types.BoolValue(true), NoBackup: types.BoolUnknown()}}
+	_, dynamo, _ = withEffectiveStorageOptions([]cassandraTableModel{}, dynamo, nil, map[string]string{"no-scaling": "true"})
+	if got := dynamo[0]; !got.RU.Equal(types.Int64Value(10)) || !got.NoBackup.Equal(types.BoolValue(false)) {
+		t.Errorf("dynamo: got %+v, want ru 10 and no_backup false", got)
+	}
+}
+
+// configureTestProvider runs Configure with the given attributes set and all others null.
+func configureTestProvider(t *testing.T, attributes map[string]tftypes.Value) provider.ConfigureResponse {
+	t.Helper()
+	ctx := context.Background()
+	p := New("test")()
+
+	var schemaResp provider.SchemaResponse
+	p.Schema(ctx, provider.SchemaRequest{}, &schemaResp)
+	objectType := schemaResp.Schema.Type().TerraformType(ctx).(tftypes.Object)
+
+	values := make(map[string]tftypes.Value, len(objectType.AttributeTypes))
+	for name, attrType := range objectType.AttributeTypes {
+		values[name] = tftypes.NewValue(attrType, nil)
+		if value, ok := attributes[name]; ok {
+			values[name] = value
+		}
+	}
+
+	var resp provider.ConfigureResponse
+	p.Configure(ctx, provider.ConfigureRequest{
+		Config: tfsdk.Config{Schema: schemaResp.Schema, Raw: tftypes.NewValue(objectType, values)},
+	}, &resp)
+	return resp
+}
+
+func TestConfigureHost(t *testing.T) {
+	t.Setenv("SCALARDB_HOST", "")
+	os.Unsetenv("SCALARDB_HOST")
+	t.Setenv("SCALARDB_ENDPOINTS", "")
+	os.Unsetenv("SCALARDB_ENDPOINTS")
+
+	tests := []struct {
+		name       string
+		attributes map[string]tftypes.Value
+		wantErr    bool
+	}{
+		{name: "known", attributes: map[string]tftypes.Value{"host": tftypes.NewValue(tftypes.String, "scalardb")}},
+		{name: "unset", wantErr: true},
+		{name: "empty", attributes: map[string]tftypes.Value{"host": tftypes.NewValue(tftypes.String, "")}, wantErr: true},
+		// The host comes from the output of a resource that is not created yet
+		{name: "unknown host", attributes: map[string]tftypes.Value{"host": tftypes.NewValue(tftypes.String, tftypes.UnknownValue)}},
+		{name: "unknown endpoints", attributes: map[string]tftypes.Value{
+			"endpoints": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, tftypes.UnknownValue),
+		}},
+		{name: "unknown endpoint", attributes: map[string]tftypes.Value{
+			"endpoints": tftypes.NewValue(tftypes.List{ElementType: tftypes.String}, []tftypes.Value{
+				tftypes.NewValue(tftypes.String, tftypes.UnknownValue),
+			}),
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := configureTestProvider(t, tt.attributes)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("got diagnostics %v, want error %t", resp.Diagnostics, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestConfigurePort(t *testing.T) {
+	host := tftypes.NewValue(tftypes.String, "scalardb")
+
+	tests := []struct {
+		name    string
+		port    any
+		env     string
+		wantErr bool
+	}{
+		{name: "default"},
+		{name: "configured", port: 60052},
+		{name: "zero", port: 0, wantErr: true},
+		{name: "too large", port: 65536, wantErr: true},
+		{name: "environment", env: "60052"},
+		{name: "environment out of range", env: "70000", wantErr: true},
+		{name: "environment not a number", env: "port", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("SCALARDB_PORT", tt.env)
+			if tt.env == "" {
+				os.Unsetenv("SCALARDB_PORT")
+			}
+			attributes := map[string]tftypes.Value{"host": host}
+			if tt.port != nil {
+				attributes["port"] = tftypes.NewValue(tftypes.Number, tt.port)
+			}
+
+			resp := configureTestProvider(t, attributes)
+			if resp.Diagnostics.HasError() != tt.wantErr {
+				t.Errorf("got diagnostics %v, want error %t", resp.Diagnostics, tt.wantErr)
+			}
+		})
+	}
+}
@@ -2,114 +2,569 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
 )
 
-func resourceScalarDBNamespace() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceScalarDBNamespaceCreate,
-		ReadContext:   resourceScalarDBNamespaceRead,
-		DeleteContext: resourceScalarDBNamespaceDelete,
-		Schema: map[string]*schema.Schema{
-			"name": {
-				Type:        schema.TypeString,
+var (
+	_ resource.ResourceWithConfigure    = (*namespaceResource)(nil)
+	_ resource.ResourceWithImportState  = (*namespaceResource)(nil)
+	_ resource.ResourceWithModifyPlan   = (*namespaceResource)(nil)
+	_ resource.ResourceWithUpgradeState = (*namespaceResource)(nil)
+)
+
+// namespaceResource manages a ScalarDB namespace.
+type namespaceResource struct {
+	meta *providerMeta
+}
+
+// namespaceResourceModel is the state of scalardb_namespace.
+type namespaceResourceModel struct {
+	ID               types.String              `tfsdk:"id"`
+	Name             types.String              `tfsdk:"name"`
+	DurableWrites    types.Bool                `tfsdk:"durable_writes"`
+	Cassandra        []cassandraNamespaceModel `tfsdk:"cassandra"`
+	Dynamo           []dynamoModel             `tfsdk:"dynamo"`
+	Cosmos           []cosmosModel             `tfsdk:"cosmos"`
+	Options          types.Map                 `tfsdk:"options"`
+	EffectiveOptions types.Map                 `tfsdk:"effective_options"`
+	ForceDestroy     types.Bool                `tfsdk:"force_destroy"`
+	RepairOnDrift    types.Bool                `tfsdk:"repair_on_drift"`
+	MetadataInSync   types.Bool                `tfsdk:"metadata_in_sync"`
+}
+
+func newNamespaceResource() resource.Resource {
+	return &namespaceResource{}
+}
+
+func (r *namespaceResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_namespace"
+}
+
+func (r *namespaceResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     2,
+		Description: "Manages a ScalarDB namespace.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the namespace. Dots and backslashes in the name are escaped with a backslash.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"name": schema.StringAttribute{
 				Required:    true,
-				ForceNew:    true,
 				Description: "The name of the namespace.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"replication_factor": {
-				Type:        schema.TypeInt,
+			"durable_writes": schema.BoolAttribute{
 				Optional:    true,
-				Default:     1,
-				ForceNew:    true,
-				Description: "The replication factor for the namespace.",
+				Computed:    true,
+				Description: "Whether to use durable writes for the namespace. Defaults to the provider-level default, or true.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+					boolplanmodifier.RequiresReplace(),
+				},
 			},
-			"strategy_class": {
-				Type:        schema.TypeString,
+			"options": schema.MapAttribute{
+				ElementType: types.StringType,
 				Optional:    true,
-				Default:     "SimpleStrategy",
-				ForceNew:    true,
-				Description: "The replication strategy class for the namespace.",
+				Description: "Additional creation options passed verbatim to ScalarDB, e.g. `ru` or `no-backup` for DynamoDB. They take precedence over the options derived from the other attributes.",
+				Validators:  optionsValidators(),
+				PlanModifiers: []planmodifier.Map{
+					requiresReplaceIfMapChanged(),
+				},
+			},
+			"effective_options": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The creation options sent to ScalarDB, including the provider-level defaults.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
 			},
-			"durable_writes": {
-				Type:        schema.TypeBool,
+			"force_destroy": schema.BoolAttribute{
 				Optional:    true,
-				Default:     true,
-				ForceNew:    true,
-				Description: "Whether to use durable writes for the namespace.",
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to drop all tables in the namespace when destroying it. If false, destroying a namespace that still contains tables fails. Tables are dropped even if their scalardb_table resources set deletion_protection, since the provider cannot see the state of other resources; the plan warns about the tables to be dropped.",
+			},
+			"repair_on_drift": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to repair the namespace metadata with RepairNamespace when it is out of sync.",
+			},
+			"metadata_in_sync": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the namespace metadata in ScalarDB matches the configuration.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
 			},
 		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+		Blocks: map[string]schema.Block{
+			"cassandra": cassandraNamespaceBlock(),
+			"dynamo":    dynamoBlock(),
+			"cosmos":    cosmosBlock(),
 		},
 	}
 }
 
-func resourceScalarDBNamespaceCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+func (r *namespaceResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = configureMeta(req.ProviderData, &resp.Diagnostics)
+}
 
-	name := d.Get("name").(string)
+func (r *namespaceResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan namespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	options := make(map[string]interface{})
-	options["replication_factor"] = d.Get("replication_factor").(int)
-	options["strategy_class"] = d.Get("strategy_class").(string)
-	options["durable_writes"] = d.Get("durable_writes").(bool)
+	name := plan.Name.ValueString()
+	options := expandNamespaceOptions(&plan, r.meta)
 
-	err := client.CreateNamespace(ctx, name, options)
+	err := r.meta.client.CreateNamespace(ctx, name, options)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
 	}
 
-	d.SetId(name)
+	plan.ID = types.StringValue(namespaceID(name))
+	plan.EffectiveOptions = stringMapValue(options.Map())
+	plan.setEffectiveOptions(options.Map())
+
+	if _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+	}
 
-	return resourceScalarDBNamespaceRead(ctx, d, m)
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceScalarDBNamespaceRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+func (r *namespaceResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state namespaceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	var diags diag.Diagnostics
+	exists, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-	name := d.Id()
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
 
-	exists, err := client.NamespaceExists(ctx, name)
+// read refreshes the metadata status of the namespace. It returns false if the namespace
+// no longer exists and should be removed from the state, so that it is created again.
+// A namespace that exists in the storage but is missing from the namespace names
+// ScalarDB lists is out of sync.
+func (r *namespaceResource) read(ctx context.Context, state *namespaceResourceModel) (bool, error) {
+	name, err := parseNamespaceID(state.ID.ValueString())
 	if err != nil {
-		return diag.FromErr(err)
+		return false, err
 	}
 
+	exists, err := r.meta.client.NamespaceExists(ctx, name)
+	if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+		return false, err
+	}
 	if !exists {
-		d.SetId("")
-		return diags
+		return false, nil
 	}
 
-	// In a real implementation, we would fetch the namespace details from ScalarDB
-	// and update the state with the actual values.
-	// For now, we'll just use the values from the configuration.
+	names, err := r.meta.client.GetNamespaceNames(ctx)
+	if err != nil {
+		return false, err
+	}
+	state.MetadataInSync = types.BoolValue(slices.Contains(names, name))
 
-	return diags
+	return true, nil
 }
 
-func resourceScalarDBNamespaceUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
+func (r *namespaceResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state namespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
 	// In ScalarDB, namespace properties like replication_factor cannot be updated
-	// after creation. So this is a no-op.
-	return resourceScalarDBNamespaceRead(ctx, d, m)
+	// after creation. The only thing Update does is repairing out-of-sync metadata.
+	if !plan.MetadataInSync.Equal(state.MetadataInSync) && plan.RepairOnDrift.ValueBool() {
+		err := r.meta.client.RepairNamespace(ctx, plan.Name.ValueString(), expandNamespaceOptions(&plan, r.meta))
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+			return
+		}
+	}
+
+	if _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
 }
 
-func resourceScalarDBNamespaceDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+func (r *namespaceResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state namespaceResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	var diags diag.Diagnostics
+	client := r.meta.client
+
+	name, err := parseNamespaceID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID format", err.Error())
+		return
+	}
+
+	exists, err := client.NamespaceExists(ctx, name)
+	if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
 
-	name := d.Id()
+	if !exists {
+		return
+	}
 
-	err := client.DeleteNamespace(ctx, name)
+	tableNames, err := client.GetNamespaceTableNames(ctx, name)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+
+	if len(tableNames) > 0 {
+		sort.Strings(tableNames)
+
+		if !state.ForceDestroy.ValueBool() {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Namespace %s is not empty", name),
+				fmt.Sprintf("The namespace still contains the following tables: %s. "+
+					"Drop them first, or set force_destroy = true to drop them along with the namespace.",
+					strings.Join(tableNames, ", ")),
+			)
+			return
+		}
+
+		for _, tableName := range tableNames {
+			if err := client.DeleteTable(ctx, name, tableName); err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+				resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+				return
+			}
+		}
 	}
 
-	d.SetId("")
+	err = client.DeleteNamespace(ctx, name)
+	if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+	}
+}
+
+// ImportState imports a namespace by name. ScalarDB does not return the namespace options,
+// so the attributes are assumed to have their default values.
+func (r *namespaceResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	name, err := parseNamespaceID(req.ID)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID format", err.Error())
+		return
+	}
+
+	state := namespaceResourceModel{
+		ID:               types.StringValue(namespaceID(name)),
+		Name:             types.StringValue(name),
+		DurableWrites:    types.BoolValue(true),
+		Cassandra:        []cassandraNamespaceModel{},
+		Dynamo:           []dynamoModel{},
+		Cosmos:           []cosmosModel{},
+		Options:          types.MapNull(types.StringType),
+		EffectiveOptions: types.MapNull(types.StringType),
+		ForceDestroy:     types.BoolValue(false),
+		RepairOnDrift:    types.BoolValue(false),
+		MetadataInSync:   types.BoolNull(),
+	}
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
 
+// ModifyPlan checks the option blocks against the backend, plans the effective creation
+// options of new namespaces, and plans a repair when the metadata is out of sync.
+func (r *namespaceResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if r.meta == nil {
+		return
+	}
+	if req.Plan.Raw.IsNull() {
+		var state namespaceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if !resp.Diagnostics.HasError() {
+			r.warnForceDestroy(ctx, &state, &resp.Diagnostics)
+		}
+		return
+	}
+
+	var plan namespaceResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	set := blocksSet(len(plan.Cassandra), len(plan.Dynamo), len(plan.Cosmos))
+	if err := checkBackendBlocks(r.meta.backend, set, expandOptions(plan.Options)); err != nil {
+		resp.Diagnostics.AddError("Storage options do not match the backend", err.Error())
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		// Changing the provider defaults does not replace existing namespaces,
+		// so the effective options are only planned for new ones. Unconfigured option
+		// attributes are unknown in the plan, so the configuration is checked instead.
+		var config namespaceResourceModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if config.optionsKnown() {
+			options := expandNamespaceOptions(&plan, r.meta).Map()
+			plan.EffectiveOptions = stringMapValue(options)
+			plan.setEffectiveOptions(options)
+		}
+	} else {
+		var state namespaceResourceModel
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		plan.MetadataInSync = planMetadataInSync(plan.RepairOnDrift, state.MetadataInSync)
+		if len(resp.RequiresReplace) > 0 {
+			r.warnForceDestroy(ctx, &state, &resp.Diagnostics)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// warnForceDestroy warns that destroying a namespace with force_destroy drops its tables.
+// The provider cannot see the state of other resources, so tables are dropped even if
+// their scalardb_table resources set deletion_protection. Errors are left to Delete.
+func (r *namespaceResource) warnForceDestroy(ctx context.Context, state *namespaceResourceModel, diags *diag.Diagnostics) {
+	if !state.ForceDestroy.ValueBool() {
+		return
+	}
+	name, err := parseNamespaceID(state.ID.ValueString())
+	if err != nil {
+		return
+	}
+	tableNames, err := r.meta.client.GetNamespaceTableNames(ctx, name)
+	if err != nil || len(tableNames) == 0 {
+		return
+	}
+	sort.Strings(tableNames)
+	diags.AddWarning(
+		fmt.Sprintf("Destroying namespace %s drops its tables", name),
+		fmt.Sprintf("force_destroy is set, so the following tables are dropped along with the namespace, "+
+			"even if their scalardb_table resources set deletion_protection: %s.", strings.Join(tableNames, ", ")),
+	)
+}
+
+// optionsKnown reports whether all attributes that contribute to the creation options are known.
+func (m *namespaceResourceModel) optionsKnown() bool {
+	values := []attr.Value{m.DurableWrites, m.Options}
+	for _, b := range m.Cassandra {
+		values = append(values, b.ReplicationStrategy, b.ReplicationFactor)
+	}
+	return allKnown(append(values, storageValues(m.Dynamo, m.Cosmos)...)...)
+}
+
+// expandNamespaceOptions builds the namespace creation options from the resource model.
+// The provider-level defaults apply to the options that the namespace does not set.
+func expandNamespaceOptions(m *namespaceResourceModel, meta *providerMeta) *scalardb.NamespaceOptions {
+	options := &scalardb.NamespaceOptions{
+		DurableWrites: boolPointer(m.DurableWrites),
+		Storage:       expandStorageOptions(m.Cassandra, m.Dynamo, m.Cosmos),
+	}
+	options.Options = mergeOptions(meta.defaultNamespaceOptions, options.Map(), expandOptions(m.Options))
+	return options
+}
+
+// setEffectiveOptions sets the option attributes that are not configured to the
+// effective creation options.
+func (m *namespaceResourceModel) setEffectiveOptions(options map[string]string) {
+	m.DurableWrites = effectiveBool(m.DurableWrites, options, "durable_writes", true)
+	m.Cassandra, m.Dynamo, m.Cosmos = withEffectiveStorageOptions(m.Cassandra, m.Dynamo, m.Cosmos, options)
+}
+
+func (r *namespaceResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	return map[int64]resource.StateUpgrader{
+		// Version 0 is the state written by the SDKv2 provider
+		0: {
+			PriorSchema: namespaceSchemaV0(),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state namespaceResourceModelV0
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				resp.Diagnostics.Append(upgradeNamespaceStateV0(&state)...)
+				upgradeNamespaceState(ctx, resp, state.namespaceResourceModel)
+			},
+		},
+		// Version 1 has the shape of the SDKv2 provider without the attributes of the
+		// baseline release, and its ID is the unescaped name
+		1: {
+			PriorSchema: namespaceSchemaV1(),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				var state namespaceResourceModel
+				resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+				if resp.Diagnostics.HasError() {
+					return
+				}
+				upgradeNamespaceState(ctx, resp, state)
+			},
+		},
+	}
+}
+
+// namespaceResourceModelV0 is the state of scalardb_namespace written by the SDKv2 provider.
+// The first releases had replication_factor and strategy_class instead of the cassandra block.
+type namespaceResourceModelV0 struct {
+	namespaceResourceModel
+	ReplicationFactor types.Int64  `tfsdk:"replication_factor"`
+	StrategyClass     types.String `tfsdk:"strategy_class"`
+}
+
+// upgradeNamespaceState saves state of an older version. The ID is rebuilt from the name,
+// since IDs of older versions did not escape it.
+func upgradeNamespaceState(ctx context.Context, resp *resource.UpgradeStateResponse, state namespaceResourceModel) {
+	state.ID = types.StringValue(namespaceID(state.Name.ValueString()))
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// upgradeNamespaceStateV0 fills in the attributes that SDKv2 state may lack, normalizes
+// empty values the way the framework provider records them, and moves replication_factor
+// and strategy_class into the cassandra block. Values the block does not accept are
+// dropped with a warning.
+func upgradeNamespaceStateV0(state *namespaceResourceModelV0) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	state.DurableWrites = boolValueOrDefault(state.DurableWrites, true)
+	state.ForceDestroy = boolValueOrDefault(state.ForceDestroy, false)
+	state.RepairOnDrift = boolValueOrDefault(state.RepairOnDrift, false)
+	state.Options = nullIfEmpty(state.Options)
+	if state.Cassandra == nil {
+		state.Cassandra = []cassandraNamespaceModel{}
+	}
+	if state.Dynamo == nil {
+		state.Dynamo = []dynamoModel{}
+	}
+	if state.Cosmos == nil {
+		state.Cosmos = []cosmosModel{}
+	}
+
+	if state.ReplicationFactor.IsNull() && state.StrategyClass.IsNull() {
+		return diags
+	}
+	if len(state.Cassandra) > 0 {
+		diags.AddWarning(
+			fmt.Sprintf("Ignoring replication options of namespace %s", state.Name.ValueString()),
+			"The state has both replication_factor or strategy_class and a cassandra block. The cassandra block is kept.",
+		)
+		return diags
+	}
+
+	cassandra := cassandraNamespaceModel{
+		ReplicationStrategy: types.StringUnknown(),
+		ReplicationFactor:   types.Int64Unknown(),
+	}
+	if v := state.StrategyClass.ValueString(); v != "" {
+		if slices.Contains(replicationStrategies, v) {
+			cassandra.ReplicationStrategy = types.StringValue(v)
+		} else {
+			diags.AddWarning(
+				fmt.Sprintf("Dropping strategy_class of namespace %s", state.Name.ValueString()),
+				fmt.Sprintf("%q is not a replication strategy of the cassandra block. Valid values are %s.", v, strings.Join(replicationStrategies, ", ")),
+			)
+		}
+	}
+	if !state.ReplicationFactor.IsNull() {
+		if n := state.ReplicationFactor.ValueInt64(); n >= 1 {
+			cassandra.ReplicationFactor = types.Int64Value(n)
+		} else {
+			diags.AddWarning(
+				fmt.Sprintf("Dropping replication_factor of namespace %s", state.Name.ValueString()),
+				fmt.Sprintf("The replication factor must be at least 1, got %d.", n),
+			)
+		}
+	}
+	if !cassandra.ReplicationStrategy.IsUnknown() || !cassandra.ReplicationFactor.IsUnknown() {
+		// An attribute that could not be mapped is recorded with the default of ScalarDB
+		state.Cassandra = []cassandraNamespaceModel{cassandra.withEffectiveOptions(nil)}
+	}
 	return diags
 }
+
+// namespaceSchemaV0 returns the schema of scalardb_namespace in the SDKv2 provider. It
+// includes the attributes of the baseline release that the cassandra block replaced.
+func namespaceSchemaV0() *schema.Schema {
+	s := namespaceSchemaV1()
+	s.Attributes["replication_factor"] = schema.Int64Attribute{Optional: true}
+	s.Attributes["strategy_class"] = schema.StringAttribute{Optional: true}
+	return s
+}
+
+// namespaceSchemaV1 returns the schema of scalardb_namespace in the last SDKv2 releases
+// and the first framework release.
+func namespaceSchemaV1() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                schema.StringAttribute{Computed: true},
+			"name":              schema.StringAttribute{Required: true},
+			"durable_writes":    schema.BoolAttribute{Optional: true},
+			"options":           schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"effective_options": schema.MapAttribute{ElementType: types.StringType, Computed: true},
+			"force_destroy":     schema.BoolAttribute{Optional: true},
+			"repair_on_drift":   schema.BoolAttribute{Optional: true},
+			"metadata_in_sync":  schema.BoolAttribute{Computed: true},
+		},
+		Blocks: map[string]schema.Block{
+			"cassandra": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"replication_strategy": schema.StringAttribute{Optional: true},
+						"replication_factor":   schema.Int64Attribute{Optional: true},
+					},
+				},
+			},
+			"dynamo": dynamoBlockV0(),
+			"cosmos": cosmosBlockV0(),
+		},
+	}
+}
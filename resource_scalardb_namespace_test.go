@@ -0,0 +1,334 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	"github.com/hashicorp/terraform-plugin-testing/terraform"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+)
+
+func TestNamespaceUpgradeStateV0(t *testing.T) {
+	tests := []struct {
+		fixture   string
+		cassandra []cassandraNamespaceModel
+		warnings  int
+	}{
+		// replication_factor and strategy_class move into the cassandra block
+		{
+			fixture: "scalardb_namespace_v0.json",
+			cassandra: []cassandraNamespaceModel{{
+				ReplicationStrategy: types.StringValue("SimpleStrategy"),
+				ReplicationFactor:   types.Int64Value(1),
+			}},
+		},
+		{fixture: "scalardb_namespace_v0_minimal.json", cassandra: []cassandraNamespaceModel{}},
+		// A strategy the cassandra block does not accept is dropped with a warning
+		{
+			fixture: "scalardb_namespace_v0_unmapped.json",
+			cassandra: []cassandraNamespaceModel{{
+				ReplicationStrategy: types.StringValue("SimpleStrategy"),
+				ReplicationFactor:   types.Int64Value(3),
+			}},
+			warnings: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			state, warnings := upgradeTestState(t, &namespaceResource{}, 0, tt.fixture)
+			if len(warnings) != tt.warnings {
+				t.Errorf("got warnings %v, want %d", warnings, tt.warnings)
+			}
+
+			var m namespaceResourceModel
+			if diags := state.Get(context.Background(), &m); diags.HasError() {
+				t.Fatalf("failed to decode upgraded state: %v", diags)
+			}
+
+			if got := m.ID.ValueString(); got != "example_namespace" {
+				t.Errorf("id = %q", got)
+			}
+			if !m.DurableWrites.ValueBool() {
+				t.Error("durable_writes should default to true")
+			}
+			if m.ForceDestroy.IsNull() || m.ForceDestroy.ValueBool() {
+				t.Errorf("force_destroy = %s, want false", m.ForceDestroy)
+			}
+			if m.RepairOnDrift.IsNull() || m.RepairOnDrift.ValueBool() {
+				t.Errorf("repair_on_drift = %s, want false", m.RepairOnDrift)
+			}
+			if !m.Options.IsNull() {
+				t.Errorf("empty options should be null, got %s", m.Options)
+			}
+			if m.Cassandra == nil || m.Dynamo == nil || m.Cosmos == nil {
+				t.Fatal("option blocks should be empty, not null")
+			}
+			if !slices.Equal(m.Cassandra, tt.cassandra) {
+				t.Errorf("cassandra = %+v, want %+v", m.Cassandra, tt.cassandra)
+			}
+		})
+	}
+}
+
+func TestNamespaceUpgradeStateV1(t *testing.T) {
+	state, warnings := upgradeTestState(t, &namespaceResource{}, 1, "scalardb_namespace_v1.json")
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+
+	var m namespaceResourceModel
+	if diags := state.Get(context.Background(), &m); diags.HasError() {
+		t.Fatalf("failed to decode upgraded state: %v", diags)
+	}
+
+	if got, want := m.ID.ValueString(), `example\.app`; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	if got := m.Name.ValueString(); got != "example.app" {
+		t.Errorf("name = %q", got)
+	}
+}
+
+func TestNamespaceReadMetadataInSync(t *testing.T) {
+	tests := []struct {
+		name       string
+		namespaces map[string]bool
+		wantExists bool
+		wantInSync bool
+	}{
+		{name: "in sync", namespaces: map[string]bool{"app": true}, wantExists: true, wantInSync: true},
+		// The namespace exists in the storage, but ScalarDB does not list it
+		{name: "drifted", namespaces: map[string]bool{"app": false}, wantExists: true},
+		// A dropped namespace is removed from the state to be created again, not repaired
+		{name: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &namespaceResource{meta: &providerMeta{client: &fakeAdminClient{namespaces: tt.namespaces}}}
+			state := namespaceResourceModel{
+				ID:            types.StringValue(namespaceID("app")),
+				RepairOnDrift: types.BoolValue(true),
+			}
+
+			exists, err := r.read(context.Background(), &state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tt.wantExists {
+				t.Fatalf("exists = %t, want %t", exists, tt.wantExists)
+			}
+			if exists && !state.MetadataInSync.Equal(types.BoolValue(tt.wantInSync)) {
+				t.Errorf("metadata_in_sync = %s, want %t", state.MetadataInSync, tt.wantInSync)
+			}
+		})
+	}
+}
+
+func TestNamespaceWarnForceDestroy(t *testing.T) {
+	client := &fakeAdminClient{tables: map[string]*scalardb.TableDefinition{
+		"app.orders": {}, "app.customers": {}, "other.items": {},
+	}}
+	r := &namespaceResource{meta: &providerMeta{client: client}}
+	tests := []struct {
+		name         string
+		namespace    string
+		forceDestroy bool
+		want         string
+	}{
+		{name: "force destroy", namespace: "app", forceDestroy: true, want: "customers, orders"},
+		{name: "no force destroy", namespace: "app"},
+		{name: "empty namespace", namespace: "empty", forceDestroy: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			state := namespaceResourceModel{
+				ID:           types.StringValue(namespaceID(tt.namespace)),
+				ForceDestroy: types.BoolValue(tt.forceDestroy),
+			}
+			var diags diag.Diagnostics
+			r.warnForceDestroy(context.Background(), &state, &diags)
+			if tt.want == "" {
+				if len(diags) != 0 {
+					t.Fatalf("got diagnostics %v, want none", diags)
+				}
+				return
+			}
+			if len(diags) != 1 || diags[0].Severity() != diag.SeverityWarning || !strings.Contains(diags[0].Detail(), tt.want) {
+				t.Fatalf("got diagnostics %v, want a warning listing %s", diags, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccNamespace(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+
+	config := func(forceDestroy, repairOnDrift bool) string {
+		return testAccProviderConfig(server, fmt.Sprintf(`
+resource "scalardb_namespace" "test" {
+  name            = "acc.ns"
+  force_destroy   = %t
+  repair_on_drift = %t
+}
+`, forceDestroy, repairOnDrift))
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckNamespaceExists(server, "acc.ns", false),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: config(false, false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNamespaceExists(server, "acc.ns", true),
+					resource.TestCheckResourceAttr("scalardb_namespace.test", "id", `acc\.ns`),
+					resource.TestCheckResourceAttr("scalardb_namespace.test", "durable_writes", "true"),
+					resource.TestCheckResourceAttr("scalardb_namespace.test", "metadata_in_sync", "true"),
+				),
+			},
+			// Import
+			{
+				ResourceName:            "scalardb_namespace.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"effective_options"},
+			},
+			// Update in place
+			{
+				Config: config(true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_namespace.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.TestCheckResourceAttr("scalardb_namespace.test", "force_destroy", "true"),
+			},
+			// Drift: a namespace dropped outside of Terraform is created again
+			{
+				PreConfig: func() {
+					_, err := server.DropNamespace(ctx, &pb.DropNamespaceRequest{NamespaceName: "acc.ns"})
+					testAccMust(t, err)
+				},
+				Config: config(true, false),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_namespace.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckNamespaceExists(server, "acc.ns", true),
+			},
+			// Drift with repair_on_drift: the metadata is repaired in place
+			{
+				Config: config(true, true),
+			},
+			{
+				PreConfig: func() { server.UnlistNamespace("acc.ns") },
+				Config:    config(true, true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_namespace.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckNamespaceExists(server, "acc.ns", true),
+					resource.TestCheckResourceAttr("scalardb_namespace.test", "metadata_in_sync", "true"),
+					func(*terraform.State) error {
+						if got := server.Calls("RepairNamespace"); got != 1 {
+							return fmt.Errorf("RepairNamespace called %d times, want 1", got)
+						}
+						return nil
+					},
+				),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceUnavailable(t *testing.T) {
+	server := testAccMockServer(t)
+	config := testAccProviderConfig(server, `
+resource "scalardb_namespace" "test" {
+  name = "acc"
+}
+`)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckNamespaceExists(server, "acc", false),
+		Steps: []resource.TestStep{
+			// Every attempt of the client fails
+			{
+				PreConfig:   func() { server.InjectFault("CreateNamespace", mock.Unavailable(scalardb.MaxAttempts)) },
+				Config:      config,
+				ExpectError: regexp.MustCompile(`ScalarDB is unavailable`),
+			},
+			// The last attempt succeeds
+			{
+				PreConfig: func() { server.InjectFault("CreateNamespace", mock.Unavailable(scalardb.MaxAttempts-1)) },
+				Config:    config,
+				Check:     testAccCheckNamespaceExists(server, "acc", true),
+			},
+		},
+	})
+}
+
+func TestAccNamespaceFailover(t *testing.T) {
+	// Two nodes of one cluster share its state.
+	cluster := mock.NewServer(mock.WithLogger(nil))
+	var nodes []*mock.Instance
+	for i := 0; i < 2; i++ {
+		listener, err := net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			t.Fatal(err)
+		}
+		node := cluster.Serve(listener)
+		t.Cleanup(node.Stop)
+		nodes = append(nodes, node)
+	}
+	config := func(names ...string) string {
+		config := fmt.Sprintf(`
+provider "scalardb" {
+  endpoints = ["127.0.0.1:%d", "127.0.0.1:%d"]
+}
+`, nodes[0].Port(), nodes[1].Port())
+		for _, name := range names {
+			config += fmt.Sprintf(`
+resource "scalardb_namespace" %[1]q {
+  name = %[1]q
+}
+`, name)
+		}
+		return config
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckNamespaceExists(nodes[1], "first", false),
+		Steps: []resource.TestStep{
+			{
+				Config: config("first"),
+				Check:  testAccCheckNamespaceExists(nodes[1], "first", true),
+			},
+			// The remaining node serves the apply after one is lost
+			{
+				PreConfig: func() { nodes[0].Stop() },
+				Config:    config("first", "second"),
+				Check:     testAccCheckNamespaceExists(nodes[1], "second", true),
+			},
+		},
+	})
+}
@@ -0,0 +1,700 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/hashicorp/go-uuid"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-framework/types/basetypes"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+)
+
+var (
+	_ resource.ResourceWithConfigure  = (*schemaResource)(nil)
+	_ resource.ResourceWithModifyPlan = (*schemaResource)(nil)
+)
+
+// schemaResource manages the tables of a Schema Loader JSON document.
+type schemaResource struct {
+	meta *providerMeta
+}
+
+// schemaResourceModel is the state of scalardb_schema.
+type schemaResourceModel struct {
+	ID                types.String          `tfsdk:"id"`
+	Schema            schemaLoaderJSONValue `tfsdk:"schema"`
+	Tables            types.Map             `tfsdk:"tables"`
+	CreatedNamespaces types.Set             `tfsdk:"created_namespaces"`
+}
+
+func newSchemaResource() resource.Resource {
+	return &schemaResource{}
+}
+
+func (r *schemaResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_schema"
+}
+
+func (r *schemaResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Manages the namespaces and tables of a ScalarDB Schema Loader JSON document.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "A random ID of the schema.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"schema": schema.StringAttribute{
+				CustomType:  schemaLoaderJSONType{},
+				Required:    true,
+				Description: "A schema in the ScalarDB Schema Loader JSON format, e.g. from file() or jsonencode().",
+				Validators: []validator.String{
+					schemaLoaderJSONValidator{},
+				},
+			},
+			"tables": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The canonical Schema Loader JSON of each table, keyed by namespace.table.",
+			},
+			"created_namespaces": schema.SetAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The namespaces created by this resource. Only these are dropped when they are no longer used.",
+			},
+		},
+	}
+}
+
+func (r *schemaResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = configureMeta(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *schemaResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan schemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := schemaTablesFromState(plan.Tables)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ScalarDB schema", err.Error())
+		return
+	}
+
+	id, err := uuid.GenerateUUID()
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to generate an ID", err.Error())
+		return
+	}
+	plan.ID = types.StringValue(id)
+
+	resp.Diagnostics.Append(checkNewSchemaTables(ctx, r.meta.client, nil, tables)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created := make(map[string]bool)
+	resp.Diagnostics.Append(applySchemaChanges(ctx, r.meta, created, make(map[string]bool), nil, tables)...)
+	plan.CreatedNamespaces = stringSetValue(sortedKeys(created))
+
+	// The planned tables are saved even on errors, so that what was created is dropped on destroy.
+	// Differences from the metadata are picked up by the next refresh.
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *schemaResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state schemaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(r.read(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes the tables of the schema from their metadata. Tables that
+// no longer exist are left out, so that the next apply creates them again.
+func (r *schemaResource) read(ctx context.Context, state *schemaResourceModel) diag.Diagnostics {
+	client := r.meta.client
+
+	var diags diag.Diagnostics
+
+	tables, err := schemaTablesFromState(state.Tables)
+	if err != nil {
+		diags.AddError("Invalid ScalarDB schema", err.Error())
+		return diags
+	}
+
+	refreshed := make(map[string]string, len(tables))
+	for key, table := range tables {
+		exists, err := client.TableExists(ctx, table.Namespace, table.Name)
+		if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			return clientErrorDiagnostics(err)
+		}
+		if !exists {
+			continue
+		}
+
+		actual, err := client.GetTableSchema(ctx, table.Namespace, table.Name)
+		if errors.Is(err, scalardb.ErrNotFound) {
+			continue
+		} else if err != nil {
+			return clientErrorDiagnostics(err)
+		}
+
+		// ScalarDB does not return the creation options, so keep the declared ones
+		actual.Options = table.Definition.Options
+
+		rendered, err := renderSchemaLoaderTable(&schemaLoaderTable{
+			Namespace:   table.Namespace,
+			Name:        table.Name,
+			Transaction: table.Transaction,
+			Definition:  actual,
+		})
+		if err != nil {
+			diags.AddError("Failed to render table "+key, err.Error())
+			return diags
+		}
+		refreshed[key] = rendered
+	}
+
+	state.Tables = stringMapValue(refreshed)
+
+	return diags
+}
+
+func (r *schemaResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state schemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	oldTables, err := schemaTablesFromState(state.Tables)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ScalarDB schema", err.Error())
+		return
+	}
+
+	newTables, err := schemaTablesFromState(plan.Tables)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ScalarDB schema", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(checkNewSchemaTables(ctx, r.meta.client, oldTables, newTables)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	created := make(map[string]bool)
+	for _, ns := range listStrings(state.CreatedNamespaces.Elements()) {
+		created[ns] = true
+	}
+
+	applied := make(map[string]bool)
+	resp.Diagnostics.Append(applySchemaChanges(ctx, r.meta, created, applied, oldTables, newTables)...)
+	if resp.Diagnostics.HasError() {
+		// Record the tables changed and the namespaces created so far, and keep the prior
+		// version of the other tables
+		state.Tables = appliedSchemaTables(state.Tables, plan.Tables, applied)
+		state.CreatedNamespaces = stringSetValue(sortedKeys(created))
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+	plan.CreatedNamespaces = stringSetValue(sortedKeys(created))
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *schemaResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state schemaResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	tables, err := schemaTablesFromState(state.Tables)
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ScalarDB schema", err.Error())
+		return
+	}
+
+	created := make(map[string]bool)
+	for _, ns := range listStrings(state.CreatedNamespaces.Elements()) {
+		created[ns] = true
+	}
+
+	resp.Diagnostics.Append(applySchemaChanges(ctx, r.meta, created, make(map[string]bool), tables, nil)...)
+}
+
+// checkNewSchemaTables fails if any table that is added to the schema already exists.
+// The resource only manages the tables it creates, and drops them when they are removed
+// from the schema, so existing tables are not adopted.
+func checkNewSchemaTables(ctx context.Context, client scalardb.AdminClient, oldTables, newTables map[string]*schemaLoaderTable) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	for _, key := range sortedKeys(newTables) {
+		if _, ok := oldTables[key]; ok {
+			continue
+		}
+		table := newTables[key]
+		exists, err := client.TableExists(ctx, table.Namespace, table.Name)
+		if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			return clientErrorDiagnostics(err)
+		}
+		if exists {
+			diags.AddError(
+				fmt.Sprintf("Table %s already exists", key),
+				"scalardb_schema only manages the tables it creates. Remove the table from the schema, "+
+					"or import it as a scalardb_table resource to manage it with Terraform.",
+			)
+		}
+	}
+
+	return diags
+}
+
+// applySchemaChanges creates, alters and drops namespaces and tables to turn oldTables into newTables.
+// created holds the namespaces created by the resource and is updated as namespaces are created and dropped.
+// applied records the keys of the tables whose changes have been applied, so that the state can be
+// saved when a later change fails.
+func applySchemaChanges(ctx context.Context, meta *providerMeta, created, applied map[string]bool, oldTables, newTables map[string]*schemaLoaderTable) diag.Diagnostics {
+	client := meta.client
+
+	var diags diag.Diagnostics
+
+	// Create missing namespaces. Table entries only hold table options, so namespaces
+	// are created with the provider-level defaults.
+	for _, ns := range schemaNamespaces(newTables) {
+		exists, err := client.NamespaceExists(ctx, ns)
+		if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			return clientErrorDiagnostics(err)
+		}
+		if exists {
+			continue
+		}
+
+		options := &scalardb.NamespaceOptions{
+			Options: meta.defaultNamespaceOptions,
+		}
+		if err := client.CreateNamespace(ctx, ns, options); err != nil {
+			return clientErrorDiagnostics(err)
+		}
+		created[ns] = true
+	}
+
+	// Create new tables and alter changed ones
+	for _, key := range sortedKeys(newTables) {
+		table := newTables[key]
+		old, ok := oldTables[key]
+		if !ok {
+			def := *table.Definition
+			def.Options = mergeOptions(meta.defaultTableOptions, table.Definition.Options)
+			if err := client.CreateTable(ctx, table.Namespace, table.Name, &def); err != nil {
+				return clientErrorDiagnostics(err)
+			}
+			applied[key] = true
+			continue
+		}
+		if err := alterSchemaTable(ctx, client, old, table); err != nil {
+			return clientErrorDiagnostics(err)
+		}
+		applied[key] = true
+	}
+
+	// Drop removed tables
+	for _, key := range sortedKeys(oldTables) {
+		if _, ok := newTables[key]; ok {
+			continue
+		}
+		table := oldTables[key]
+		if err := client.DeleteTable(ctx, table.Namespace, table.Name); err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			return clientErrorDiagnostics(err)
+		}
+		applied[key] = true
+	}
+
+	// Drop namespaces created by this resource that are no longer used
+	used := schemaNamespaces(newTables)
+	for _, ns := range sortedKeys(created) {
+		if slices.Contains(used, ns) {
+			continue
+		}
+
+		tableNames, err := client.GetNamespaceTableNames(ctx, ns)
+		if errors.Is(err, scalardb.ErrNotFound) {
+			delete(created, ns)
+			continue
+		} else if err != nil {
+			return clientErrorDiagnostics(err)
+		}
+
+		if len(tableNames) > 0 {
+			sort.Strings(tableNames)
+			diags.AddWarning(
+				fmt.Sprintf("Namespace %s was not dropped", ns),
+				fmt.Sprintf("The namespace is no longer used by the schema, but still contains tables that are not managed by it: %s.",
+					strings.Join(tableNames, ", ")),
+			)
+			delete(created, ns)
+			continue
+		}
+
+		if err := client.DeleteNamespace(ctx, ns); err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			return clientErrorDiagnostics(err)
+		}
+		delete(created, ns)
+	}
+
+	return diags
+}
+
+// appliedSchemaTables returns the prior tables with the applied changes of the planned ones.
+// Applied tables missing from the plan have been dropped.
+func appliedSchemaTables(prior, planned types.Map, applied map[string]bool) types.Map {
+	elements := maps.Clone(prior.Elements())
+	if elements == nil {
+		elements = make(map[string]attr.Value)
+	}
+	for key := range applied {
+		if v, ok := planned.Elements()[key]; ok {
+			elements[key] = v
+		} else {
+			delete(elements, key)
+		}
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// alterSchemaTable applies the changes ScalarDB supports in place: new columns and secondary indexes.
+func alterSchemaTable(ctx context.Context, client scalardb.AdminClient, old, table *schemaLoaderTable) error {
+	oldColumns := make(map[string]bool)
+	for _, column := range old.Definition.Columns {
+		oldColumns[column.Name] = true
+	}
+	for _, column := range table.Definition.Columns {
+		if oldColumns[column.Name] {
+			continue
+		}
+		if err := client.AddNewColumnToTable(ctx, table.Namespace, table.Name, column); err != nil {
+			return err
+		}
+	}
+
+	for _, colName := range table.Definition.SecondaryIndexes {
+		if slices.Contains(old.Definition.SecondaryIndexes, colName) {
+			continue
+		}
+		if err := client.CreateIndex(ctx, table.Namespace, table.Name, colName, nil); err != nil {
+			return err
+		}
+	}
+	for _, colName := range old.Definition.SecondaryIndexes {
+		if slices.Contains(table.Definition.SecondaryIndexes, colName) {
+			continue
+		}
+		if err := client.DropIndex(ctx, table.Namespace, table.Name, colName); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// schemaAlterationProblems lists the changes between two versions of a table that cannot be applied in place.
+func schemaAlterationProblems(old, table *schemaLoaderTable) []string {
+	var problems []string
+	oldDef, def := old.Definition, table.Definition
+
+	if !slices.Equal(oldDef.PartitionKey, def.PartitionKey) {
+		problems = append(problems, "partition-key")
+	}
+	if !slices.Equal(oldDef.ClusteringKey, def.ClusteringKey) {
+		problems = append(problems, "clustering-key")
+	} else {
+		for _, colName := range def.ClusteringKey {
+			if clusteringOrderOrDefault(oldDef.ClusteringOrder, colName) != clusteringOrderOrDefault(def.ClusteringOrder, colName) {
+				problems = append(problems, "clustering order of "+colName)
+			}
+		}
+	}
+
+	columnTypes := make(map[string]string)
+	for _, column := range def.Columns {
+		columnTypes[column.Name] = column.Type
+	}
+	for _, column := range oldDef.Columns {
+		newType, ok := columnTypes[column.Name]
+		if !ok {
+			problems = append(problems, "removal of column "+column.Name)
+		} else if newType != column.Type {
+			problems = append(problems, "data type of column "+column.Name)
+		}
+	}
+
+	for _, key := range sortedKeys(mergeOptions(oldDef.Options, def.Options)) {
+		if oldDef.Options[key] != def.Options[key] {
+			problems = append(problems, "option "+key)
+		}
+	}
+
+	return problems
+}
+
+// ModifyPlan plans the per-table changes of the schema and rejects changes
+// that ScalarDB cannot apply without dropping the table.
+func (r *schemaResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan schemaResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if plan.Schema.IsUnknown() {
+		plan.Tables = types.MapUnknown(types.StringType)
+		plan.CreatedNamespaces = types.SetUnknown(types.StringType)
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	tables, err := parseSchemaLoaderJSON(plan.Schema.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddAttributeError(path.Root("schema"), "Invalid ScalarDB schema", err.Error())
+		return
+	}
+
+	var state *schemaResourceModel
+	if !req.State.Raw.IsNull() {
+		state = &schemaResourceModel{}
+		resp.Diagnostics.Append(req.State.Get(ctx, state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+
+		oldTables, err := schemaTablesFromState(state.Tables)
+		if err != nil {
+			resp.Diagnostics.AddError("Invalid ScalarDB schema", err.Error())
+			return
+		}
+
+		var errs []string
+		for _, key := range sortedKeys(tables) {
+			old, ok := oldTables[key]
+			if !ok {
+				continue
+			}
+			if problems := schemaAlterationProblems(old, tables[key]); len(problems) > 0 {
+				errs = append(errs, fmt.Sprintf("%s: %s", key, strings.Join(problems, ", ")))
+			}
+		}
+		if len(errs) > 0 {
+			resp.Diagnostics.AddAttributeError(path.Root("schema"), "Unsupported schema change",
+				fmt.Sprintf("ScalarDB can only add columns and secondary indexes to existing tables. "+
+					"The following changes would require dropping and recreating the tables, which deletes their data:\n  - %s",
+					strings.Join(errs, "\n  - ")))
+			return
+		}
+	}
+
+	rendered := make(map[string]string, len(tables))
+	for key, table := range tables {
+		if rendered[key], err = renderSchemaLoaderTable(table); err != nil {
+			resp.Diagnostics.AddError("Failed to render table "+key, err.Error())
+			return
+		}
+	}
+	plan.Tables = stringMapValue(rendered)
+
+	// Namespaces are only created or dropped when the tables change
+	if state != nil {
+		if plan.Tables.Equal(state.Tables) {
+			plan.CreatedNamespaces = state.CreatedNamespaces
+		} else {
+			plan.CreatedNamespaces = types.SetUnknown(types.StringType)
+		}
+	}
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+}
+
+// schemaLoaderJSONValidator validates a Schema Loader JSON document.
+type schemaLoaderJSONValidator struct{}
+
+var _ validator.String = schemaLoaderJSONValidator{}
+
+func (v schemaLoaderJSONValidator) Description(ctx context.Context) string {
+	return "value must be a valid Schema Loader JSON document"
+}
+
+func (v schemaLoaderJSONValidator) MarkdownDescription(ctx context.Context) string {
+	return v.Description(ctx)
+}
+
+func (v schemaLoaderJSONValidator) ValidateString(ctx context.Context, req validator.StringRequest, resp *validator.StringResponse) {
+	if req.ConfigValue.IsNull() || req.ConfigValue.IsUnknown() {
+		return
+	}
+	if _, err := parseSchemaLoaderJSON(req.ConfigValue.ValueString()); err != nil {
+		resp.Diagnostics.AddAttributeError(req.Path, "Invalid ScalarDB schema", err.Error())
+	}
+}
+
+// schemaLoaderJSONType is the type of the schema attribute. Documents that describe
+// the same tables are semantically equal, so reformatting the JSON plans no changes.
+type schemaLoaderJSONType struct {
+	basetypes.StringType
+}
+
+var _ basetypes.StringTypable = schemaLoaderJSONType{}
+
+func (t schemaLoaderJSONType) Equal(o attr.Type) bool {
+	_, ok := o.(schemaLoaderJSONType)
+	return ok
+}
+
+func (t schemaLoaderJSONType) String() string {
+	return "schemaLoaderJSONType"
+}
+
+func (t schemaLoaderJSONType) ValueFromString(ctx context.Context, in basetypes.StringValue) (basetypes.StringValuable, diag.Diagnostics) {
+	return schemaLoaderJSONValue{StringValue: in}, nil
+}
+
+func (t schemaLoaderJSONType) ValueFromTerraform(ctx context.Context, in tftypes.Value) (attr.Value, error) {
+	attrValue, err := t.StringType.ValueFromTerraform(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	stringValue, ok := attrValue.(basetypes.StringValue)
+	if !ok {
+		return nil, fmt.Errorf("unexpected value type of %T", attrValue)
+	}
+	return schemaLoaderJSONValue{StringValue: stringValue}, nil
+}
+
+func (t schemaLoaderJSONType) ValueType(ctx context.Context) attr.Value {
+	return schemaLoaderJSONValue{}
+}
+
+// schemaLoaderJSONValue is a value of schemaLoaderJSONType.
+type schemaLoaderJSONValue struct {
+	basetypes.StringValue
+}
+
+var _ basetypes.StringValuableWithSemanticEquals = schemaLoaderJSONValue{}
+
+func (v schemaLoaderJSONValue) Equal(o attr.Value) bool {
+	other, ok := o.(schemaLoaderJSONValue)
+	return ok && v.StringValue.Equal(other.StringValue)
+}
+
+func (v schemaLoaderJSONValue) Type(ctx context.Context) attr.Type {
+	return schemaLoaderJSONType{}
+}
+
+// StringSemanticEquals reports whether two documents describe the same tables.
+func (v schemaLoaderJSONValue) StringSemanticEquals(ctx context.Context, newValuable basetypes.StringValuable) (bool, diag.Diagnostics) {
+	var diags diag.Diagnostics
+
+	newValue, ok := newValuable.(schemaLoaderJSONValue)
+	if !ok {
+		diags.AddError("Semantic equality check error",
+			fmt.Sprintf("Expected schemaLoaderJSONValue, got %T. Please report this issue to the provider developers.", newValuable))
+		return false, diags
+	}
+
+	return equivalentSchemaLoaderJSON(v.ValueString(), newValue.ValueString()), diags
+}
+
+// equivalentSchemaLoaderJSON reports whether two documents describe the same tables.
+func equivalentSchemaLoaderJSON(old, new string) bool {
+	oldTables, err := parseSchemaLoaderJSON(old)
+	if err != nil {
+		return false
+	}
+	newTables, err := parseSchemaLoaderJSON(new)
+	if err != nil || len(oldTables) != len(newTables) {
+		return false
+	}
+
+	for key, table := range newTables {
+		oldTable, ok := oldTables[key]
+		if !ok {
+			return false
+		}
+		oldRendered, _ := renderSchemaLoaderTable(oldTable)
+		newRendered, _ := renderSchemaLoaderTable(table)
+		if oldRendered != newRendered {
+			return false
+		}
+	}
+
+	return true
+}
+
+// schemaTablesFromState parses the tables attribute.
+func schemaTablesFromState(v types.Map) (map[string]*schemaLoaderTable, error) {
+	elements := v.Elements()
+	tables := make(map[string]*schemaLoaderTable, len(elements))
+	for key, element := range elements {
+		tableJSON, ok := element.(types.String)
+		if !ok || tableJSON.IsNull() || tableJSON.IsUnknown() {
+			continue
+		}
+		table, err := parseSchemaLoaderTable(key, []byte(tableJSON.ValueString()))
+		if err != nil {
+			return nil, err
+		}
+		tables[key] = table
+	}
+	return tables, nil
+}
+
+// schemaNamespaces returns the sorted namespaces of the tables.
+func schemaNamespaces(tables map[string]*schemaLoaderTable) []string {
+	namespaces := make(map[string]bool)
+	for _, table := range tables {
+		namespaces[table.Namespace] = true
+	}
+	return sortedKeys(namespaces)
+}
+
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,266 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+	"google.golang.org/grpc/codes"
+)
+
+func TestAccSchema(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+
+	orders := `{
+      transaction   = true
+      partition-key = ["order_id"]
+      columns = {
+        order_id = "INT"
+        item     = "TEXT"
+      }
+    }`
+	ordersWithQuantity := `{
+      transaction     = true
+      partition-key   = ["order_id"]
+      secondary-index = ["item"]
+      columns = {
+        order_id = "INT"
+        item     = "TEXT"
+        quantity = "INT"
+      }
+    }`
+	items := `{
+      transaction   = true
+      partition-key = ["item"]
+      columns = {
+        item  = "TEXT"
+        price = "DOUBLE"
+      }
+    }`
+
+	config := func(tables string) string {
+		return testAccProviderConfig(server, fmt.Sprintf(`
+resource "scalardb_schema" "test" {
+  schema = jsonencode({
+    %s
+  })
+}
+`, tables))
+	}
+	initial := config(`"acc.orders" = ` + orders)
+	updated := config(`"acc.orders" = ` + ordersWithQuantity + "\n    \"acc.items\" = " + items)
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckTableExists(server, "acc", "orders", false),
+			testAccCheckTableExists(server, "acc", "items", false),
+			testAccCheckNamespaceExists(server, "acc", false),
+		),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: initial,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTableExists(server, "acc", "orders", true),
+					resource.TestCheckResourceAttrSet("scalardb_schema.test", "id"),
+					resource.TestCheckResourceAttr("scalardb_schema.test", "tables.%", "1"),
+					resource.TestCheckTypeSetElemAttr("scalardb_schema.test", "created_namespaces.*", "acc"),
+				),
+			},
+			// Update: columns, indexes and tables are added in place
+			{
+				Config: updated,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_schema.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTableExists(server, "acc", "items", true),
+					testAccCheckTableMetadata(server, "acc", "orders", func(m *pb.TableMetadata) error {
+						if m.Columns["quantity"] != pb.DataType_DATA_TYPE_INT {
+							return fmt.Errorf("column quantity: got %s", m.Columns["quantity"])
+						}
+						if !slices.Equal(m.SecondaryIndexColumnNames, []string{"item"}) {
+							return fmt.Errorf("secondary indexes: got %v", m.SecondaryIndexColumnNames)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr("scalardb_schema.test", "tables.%", "2"),
+				),
+			},
+			// Drift: a table dropped outside of Terraform is created again
+			{
+				PreConfig: func() {
+					_, err := server.DropTable(ctx, &pb.DropTableRequest{NamespaceName: "acc", TableName: "items"})
+					testAccMust(t, err)
+				},
+				Config: updated,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_schema.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckTableExists(server, "acc", "items", true),
+			},
+		},
+	})
+}
+
+// mustParseSchemaTable parses a single table of a Schema Loader document.
+func mustParseSchemaTable(t *testing.T, tableJSON string) *schemaLoaderTable {
+	t.Helper()
+	tables, err := parseSchemaLoaderJSON(`{"app.orders": ` + tableJSON + `}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tables["app.orders"]
+}
+
+func TestSchemaAlterationProblems(t *testing.T) {
+	old := `{"partition-key": ["id"], "clustering-key": ["ts"], "columns": {"id": "INT", "ts": "BIGINT", "item": "TEXT"}, "ru": 10}`
+	tests := []struct {
+		name  string
+		table string
+		want  []string
+	}{
+		{
+			name:  "new column and index",
+			table: `{"partition-key": ["id"], "clustering-key": ["ts"], "columns": {"id": "INT", "ts": "BIGINT", "item": "TEXT", "qty": "INT"}, "secondary-index": ["item"], "ru": 10}`,
+		},
+		{
+			name:  "partition key",
+			table: `{"partition-key": ["id", "item"], "clustering-key": ["ts"], "columns": {"id": "INT", "ts": "BIGINT", "item": "TEXT"}, "ru": 10}`,
+			want:  []string{"partition-key"},
+		},
+		{
+			name:  "clustering order",
+			table: `{"partition-key": ["id"], "clustering-key": ["ts DESC"], "columns": {"id": "INT", "ts": "BIGINT", "item": "TEXT"}, "ru": 10}`,
+			want:  []string{"clustering order of ts"},
+		},
+		{
+			name:  "column removed and retyped",
+			table: `{"partition-key": ["id"], "clustering-key": ["ts"], "columns": {"id": "INT", "ts": "INT"}, "ru": 10}`,
+			want:  []string{"removal of column item", "data type of column ts"},
+		},
+		{
+			name:  "option",
+			table: `{"partition-key": ["id"], "clustering-key": ["ts"], "columns": {"id": "INT", "ts": "BIGINT", "item": "TEXT"}, "ru": 20}`,
+			want:  []string{"option ru"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := schemaAlterationProblems(mustParseSchemaTable(t, old), mustParseSchemaTable(t, tt.table))
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("got %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// recordingAdminClient records the in-place changes made to tables.
+type recordingAdminClient struct {
+	fakeAdminClient
+	calls []string
+}
+
+func (c *recordingAdminClient) AddNewColumnToTable(ctx context.Context, namespace, name string, column scalardb.ColumnDefinition) error {
+	c.calls = append(c.calls, "add column "+column.Name)
+	return nil
+}
+
+func (c *recordingAdminClient) CreateIndex(ctx context.Context, namespace, table, column string, options map[string]string) error {
+	c.calls = append(c.calls, "create index "+column)
+	return nil
+}
+
+func (c *recordingAdminClient) DropIndex(ctx context.Context, namespace, table, column string) error {
+	c.calls = append(c.calls, "drop index "+column)
+	return nil
+}
+
+func TestAlterSchemaTable(t *testing.T) {
+	old := mustParseSchemaTable(t, `{"partition-key": ["id"], "columns": {"id": "INT", "item": "TEXT"}, "secondary-index": ["item"]}`)
+	table := mustParseSchemaTable(t, `{"partition-key": ["id"], "columns": {"id": "INT", "item": "TEXT", "qty": "INT", "note": "TEXT"}, "secondary-index": ["qty"]}`)
+
+	client := &recordingAdminClient{}
+	if err := alterSchemaTable(context.Background(), client, old, table); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{"add column note", "add column qty", "create index qty", "drop index item"}
+	if !slices.Equal(client.calls, want) {
+		t.Errorf("got %q, want %q", client.calls, want)
+	}
+}
+
+func TestCheckNewSchemaTables(t *testing.T) {
+	orders := mustParseSchemaTable(t, `{"partition-key": ["id"], "columns": {"id": "INT"}}`)
+	client := &fakeAdminClient{tables: map[string]*scalardb.TableDefinition{"app.orders": orders.Definition}}
+	newTables := map[string]*schemaLoaderTable{"app.orders": orders}
+
+	// An existing table that the schema did not create is not adopted
+	diags := checkNewSchemaTables(context.Background(), client, nil, newTables)
+	if !diags.HasError() {
+		t.Error("an existing table was adopted")
+	}
+	// A table the schema already manages is not checked again
+	diags = checkNewSchemaTables(context.Background(), client, newTables, newTables)
+	if diags.HasError() {
+		t.Errorf("unexpected error: %v", diags)
+	}
+}
+
+func TestApplySchemaChangesPartially(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+	meta := &providerMeta{client: &scalardb.Client{Host: "127.0.0.1", Port: server.Port()}}
+
+	tableJSON := map[string]string{
+		"app.items":  `{"partition-key": ["id"], "columns": {"id": "INT"}}`,
+		"app.logs":   `{"partition-key": ["id"], "columns": {"id": "INT"}}`,
+		"app.orders": `{"partition-key": ["id"], "columns": {"id": "INT"}}`,
+	}
+	tablesMap := func(keys ...string) types.Map {
+		elements := make(map[string]attr.Value)
+		for _, key := range keys {
+			elements[key] = types.StringValue(tableJSON[key])
+		}
+		return types.MapValueMust(types.StringType, elements)
+	}
+	prior, planned := tablesMap("app.orders"), tablesMap("app.items", "app.logs")
+
+	oldTables, err := schemaTablesFromState(prior)
+	if err != nil {
+		t.Fatal(err)
+	}
+	newTables, err := schemaTablesFromState(planned)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diags := applySchemaChanges(ctx, meta, make(map[string]bool), make(map[string]bool), nil, oldTables); diags.HasError() {
+		t.Fatal(diags)
+	}
+
+	// app.items is created, but creating app.logs fails before app.orders is dropped
+	server.InjectFault("CreateTable", mock.FailNth(2, codes.Internal))
+	applied := make(map[string]bool)
+	if diags := applySchemaChanges(ctx, meta, make(map[string]bool), applied, oldTables, newTables); !diags.HasError() {
+		t.Fatal("expected an error")
+	}
+
+	got := sortedKeys(appliedSchemaTables(prior, planned, applied).Elements())
+	if want := []string{"app.items", "app.orders"}; !slices.Equal(got, want) {
+		t.Errorf("tables = %v, want %v", got, want)
+	}
+}
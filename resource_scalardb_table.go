@@ -2,212 +2,978 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"sort"
 	"strings"
 
-	"github.com/hashicorp/terraform-plugin-sdk/v2/diag"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/schema"
-	"github.com/hashicorp/terraform-plugin-sdk/v2/helper/validation"
+	"github.com/hashicorp/terraform-plugin-framework-validators/setvalidator"
+	"github.com/hashicorp/terraform-plugin-framework-validators/stringvalidator"
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/booldefault"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/boolplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/listplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/mapplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/stringplanmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/schema/validator"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
 )
 
-func resourceScalarDBTable() *schema.Resource {
-	return &schema.Resource{
-		CreateContext: resourceScalarDBTableCreate,
-		ReadContext:   resourceScalarDBTableRead,
-		DeleteContext: resourceScalarDBTableDelete,
-		Schema: map[string]*schema.Schema{
-			"namespace": {
-				Type:        schema.TypeString,
+var (
+	_ resource.ResourceWithConfigure      = (*tableResource)(nil)
+	_ resource.ResourceWithImportState    = (*tableResource)(nil)
+	_ resource.ResourceWithModifyPlan     = (*tableResource)(nil)
+	_ resource.ResourceWithUpgradeState   = (*tableResource)(nil)
+	_ resource.ResourceWithValidateConfig = (*tableResource)(nil)
+)
+
+// tableResource manages a ScalarDB table.
+type tableResource struct {
+	meta *providerMeta
+}
+
+// tableResourceModel is the state of scalardb_table.
+type tableResourceModel struct {
+	ID                 types.String          `tfsdk:"id"`
+	Namespace          types.String          `tfsdk:"namespace"`
+	Name               types.String          `tfsdk:"name"`
+	PartitionKey       types.List            `tfsdk:"partition_key"`
+	ClusteringKey      types.List            `tfsdk:"clustering_key"`
+	Column             types.Set             `tfsdk:"column"`
+	ClusteringOrder    types.Map             `tfsdk:"clustering_order"`
+	Cassandra          []cassandraTableModel `tfsdk:"cassandra"`
+	Dynamo             []dynamoModel         `tfsdk:"dynamo"`
+	Cosmos             []cosmosModel         `tfsdk:"cosmos"`
+	Options            types.Map             `tfsdk:"options"`
+	EffectiveOptions   types.Map             `tfsdk:"effective_options"`
+	DeletionProtection types.Bool            `tfsdk:"deletion_protection"`
+	RepairOnDrift      types.Bool            `tfsdk:"repair_on_drift"`
+	MetadataInSync     types.Bool            `tfsdk:"metadata_in_sync"`
+}
+
+// columnModel is a column block of scalardb_table.
+type columnModel struct {
+	Name           types.String `tfsdk:"name"`
+	Type           types.String `tfsdk:"type"`
+	SecondaryIndex types.Bool   `tfsdk:"secondary_index"`
+	Encrypted      types.Bool   `tfsdk:"encrypted"`
+}
+
+// columnAttrTypes are the attribute types of a column block.
+var columnAttrTypes = map[string]attr.Type{
+	"name":            types.StringType,
+	"type":            types.StringType,
+	"secondary_index": types.BoolType,
+	"encrypted":       types.BoolType,
+}
+
+func newTableResource() resource.Resource {
+	return &tableResource{}
+}
+
+func (r *tableResource) Metadata(ctx context.Context, req resource.MetadataRequest, resp *resource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_table"
+}
+
+func (r *tableResource) Schema(ctx context.Context, req resource.SchemaRequest, resp *resource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Version:     3,
+		Description: "Manages a ScalarDB table.",
+		Attributes: map[string]schema.Attribute{
+			"id": schema.StringAttribute{
+				Computed:    true,
+				Description: "The ID of the table in the form namespace.table. Dots and backslashes in the names are escaped with a backslash.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"namespace": schema.StringAttribute{
 				Required:    true,
-				ForceNew:    true,
 				Description: "The namespace in which to create the table.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"name": {
-				Type:        schema.TypeString,
+			"name": schema.StringAttribute{
 				Required:    true,
-				ForceNew:    true,
 				Description: "The name of the table.",
+				PlanModifiers: []planmodifier.String{
+					stringplanmodifier.RequiresReplace(),
+				},
 			},
-			"partition_key": {
-				Type:        schema.TypeList,
+			"partition_key": schema.ListAttribute{
+				ElementType: types.StringType,
 				Required:    true,
-				ForceNew:    true,
 				Description: "The partition key columns of the table.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+				PlanModifiers: []planmodifier.List{
+					listplanmodifier.RequiresReplace(),
 				},
 			},
-			"clustering_key": {
-				Type:        schema.TypeList,
+			"clustering_key": schema.ListAttribute{
+				ElementType: types.StringType,
 				Optional:    true,
-				ForceNew:    true,
 				Description: "The clustering key columns of the table.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
+				PlanModifiers: []planmodifier.List{
+					requiresReplaceIfListChanged(),
 				},
 			},
-			"column": {
-				Type:        schema.TypeSet,
-				Required:    true,
-				ForceNew:    true,
+			"clustering_order": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "The clustering order for the table. Columns without an order are ASC.",
+				PlanModifiers: []planmodifier.Map{
+					requiresReplaceIfClusteringOrderChanged(),
+				},
+			},
+			"options": schema.MapAttribute{
+				ElementType: types.StringType,
+				Optional:    true,
+				Description: "Additional creation options passed verbatim to ScalarDB, e.g. `ru` or `no-backup` for DynamoDB. They take precedence over the options derived from the other attributes.",
+				Validators:  optionsValidators(),
+				PlanModifiers: []planmodifier.Map{
+					requiresReplaceIfMapChanged(),
+				},
+			},
+			"effective_options": schema.MapAttribute{
+				ElementType: types.StringType,
+				Computed:    true,
+				Description: "The creation options sent to ScalarDB, including the provider-level defaults.",
+				PlanModifiers: []planmodifier.Map{
+					mapplanmodifier.UseStateForUnknown(),
+				},
+			},
+			"deletion_protection": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to prevent the table from being destroyed or replaced. Destroying or replacing the table fails while this is set.",
+			},
+			"repair_on_drift": schema.BoolAttribute{
+				Optional:    true,
+				Computed:    true,
+				Default:     booldefault.StaticBool(false),
+				Description: "Whether to repair the table metadata with RepairTable when it is out of sync.",
+			},
+			"metadata_in_sync": schema.BoolAttribute{
+				Computed:    true,
+				Description: "Whether the table metadata in ScalarDB matches the configuration.",
+				PlanModifiers: []planmodifier.Bool{
+					boolplanmodifier.UseStateForUnknown(),
+				},
+			},
+		},
+		Blocks: map[string]schema.Block{
+			"column": schema.SetNestedBlock{
 				Description: "The columns of the table.",
-				Elem: &schema.Resource{
-					Schema: map[string]*schema.Schema{
-						"name": {
-							Type:        schema.TypeString,
+				Validators: []validator.Set{
+					setvalidator.IsRequired(),
+				},
+				PlanModifiers: []planmodifier.Set{
+					requiresReplaceIfColumnsChanged(),
+				},
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{
 							Required:    true,
 							Description: "The name of the column.",
 						},
-						"type": {
-							Type:     schema.TypeString,
-							Required: true,
-							ValidateFunc: validation.StringInSlice([]string{
-								"INT", "BIGINT", "TEXT", "FLOAT", "DOUBLE", "BOOLEAN", "BLOB",
-							}, false),
+						"type": schema.StringAttribute{
+							Required:    true,
 							Description: "The data type of the column.",
+							Validators: []validator.String{
+								stringvalidator.OneOf(dataTypes...),
+							},
+						},
+						"secondary_index": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Whether to create a secondary index on the column. Indexes are created and dropped without recreating the table. Defaults to false.",
+						},
+						"encrypted": schema.BoolAttribute{
+							Optional:    true,
+							Computed:    true,
+							Description: "Whether the column is encrypted. Requires ScalarDB Cluster with encryption enabled. Defaults to false.",
 						},
 					},
 				},
 			},
-			"compaction_strategy": {
-				Type:        schema.TypeString,
-				Optional:    true,
-				ForceNew:    true,
-				Default:     "SizeTieredCompactionStrategy",
-				Description: "The compaction strategy for the table.",
-			},
-			"clustering_order": {
-				Type:        schema.TypeMap,
-				Optional:    true,
-				ForceNew:    true,
-				Description: "The clustering order for the table.",
-				Elem: &schema.Schema{
-					Type: schema.TypeString,
-				},
-			},
-		},
-		Importer: &schema.ResourceImporter{
-			StateContext: schema.ImportStatePassthroughContext,
+			"cassandra": cassandraTableBlock(),
+			"dynamo":    dynamoBlock(),
+			"cosmos":    cosmosBlock(),
 		},
 	}
 }
 
-func resourceScalarDBTableCreate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+func (r *tableResource) Configure(ctx context.Context, req resource.ConfigureRequest, resp *resource.ConfigureResponse) {
+	r.meta = configureMeta(req.ProviderData, &resp.Diagnostics)
+}
+
+func (r *tableResource) Create(ctx context.Context, req resource.CreateRequest, resp *resource.CreateResponse) {
+	var plan tableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	namespace := d.Get("namespace").(string)
-	name := d.Get("name").(string)
+	namespace := plan.Namespace.ValueString()
+	name := plan.Name.ValueString()
+	table := expandTableDefinition(ctx, &plan, r.meta)
 
-	// Build columns map
-	columns := make(map[string]map[string]interface{})
-	for _, c := range d.Get("column").(*schema.Set).List() {
-		column := c.(map[string]interface{})
-		columnName := column["name"].(string)
-		columnType := column["type"].(string)
+	err := r.meta.client.CreateTable(ctx, namespace, name, table)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+
+	plan.EffectiveOptions = stringMapValue(table.CreationOptions())
+	plan.setEffectiveOptions(table.CreationOptions())
+
+	plan.ID = types.StringValue(tableID(namespace, name))
+
+	if _, _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *tableResource) Read(ctx context.Context, req resource.ReadRequest, resp *resource.ReadResponse) {
+	var state tableResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	table, exists, err := r.read(ctx, &state)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+	if !exists {
+		resp.State.RemoveResource(ctx)
+		return
+	}
 
-		columns[columnName] = map[string]interface{}{
-			"type": columnType,
+	// Secondary indexes can be changed in place, so drift in them is planned as an update
+	state.Column, err = columnsWithMetadata(ctx, state.Column, table)
+	if err != nil {
+		resp.Diagnostics.AddError("Failed to refresh columns", err.Error())
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// read refreshes the metadata status of the table and returns the table metadata. It
+// returns false if the table no longer exists and should be removed from the state, so
+// that it is created again. The columns and options are kept as configured, and only
+// whether the metadata in ScalarDB matches them is recorded.
+func (r *tableResource) read(ctx context.Context, state *tableResourceModel) (*scalardb.TableDefinition, bool, error) {
+	client := r.meta.client
+
+	namespace, name, err := parseTableID(state.ID.ValueString())
+	if err != nil {
+		return nil, false, err
+	}
+
+	exists, err := client.TableExists(ctx, namespace, name)
+	if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+		return nil, false, err
+	}
+
+	var table *scalardb.TableDefinition
+	if exists {
+		table, err = client.GetTableSchema(ctx, namespace, name)
+		if errors.Is(err, scalardb.ErrNotFound) {
+			// The table was dropped between the two calls
+			exists = false
+		} else if err != nil {
+			return nil, false, err
 		}
 	}
 
-	// Add primary key information
-	partitionKey := make([]string, 0)
-	for _, pk := range d.Get("partition_key").([]interface{}) {
-		partitionKey = append(partitionKey, pk.(string))
-		if col, ok := columns[pk.(string)]; ok {
-			col["partition_key"] = true
+	if !exists {
+		return nil, false, nil
+	}
+
+	state.Namespace = types.StringValue(namespace)
+	state.Name = types.StringValue(name)
+	state.MetadataInSync = types.BoolValue(tableSchemaMatches(expandTableDefinition(ctx, state, r.meta), table))
+
+	return table, true, nil
+}
+
+func (r *tableResource) Update(ctx context.Context, req resource.UpdateRequest, resp *resource.UpdateResponse) {
+	var plan, state tableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	namespace := plan.Namespace.ValueString()
+	name := plan.Name.ValueString()
+	table := expandTableDefinition(ctx, &plan, r.meta)
+
+	// In ScalarDB, table schema cannot be updated after creation.
+	// Update only repairs out-of-sync metadata and creates or drops secondary indexes.
+	if !plan.MetadataInSync.Equal(state.MetadataInSync) && plan.RepairOnDrift.ValueBool() {
+		err := r.meta.client.RepairTable(ctx, namespace, name, table)
+		if err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+			return
 		}
 	}
 
-	clusteringKey := make([]string, 0)
-	for _, ck := range d.Get("clustering_key").([]interface{}) {
-		clusteringKey = append(clusteringKey, ck.(string))
-		if col, ok := columns[ck.(string)]; ok {
-			col["clustering_key"] = true
+	oldIndexes := expandTableDefinition(ctx, &state, r.meta).SecondaryIndexes
+	for _, colName := range table.SecondaryIndexes {
+		if slices.Contains(oldIndexes, colName) {
+			continue
 		}
+		if err := r.meta.client.CreateIndex(ctx, namespace, name, colName, nil); err != nil {
+			resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+			return
+		}
+	}
+	for _, colName := range oldIndexes {
+		if slices.Contains(table.SecondaryIndexes, colName) {
+			continue
+		}
+		if err := r.meta.client.DropIndex(ctx, namespace, name, colName); err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+			return
+		}
+	}
+
+	if _, _, err := r.read(ctx, &plan); err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, plan)...)
+}
+
+func (r *tableResource) Delete(ctx context.Context, req resource.DeleteRequest, resp *resource.DeleteResponse) {
+	var state tableResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if state.DeletionProtection.ValueBool() {
+		resp.Diagnostics.AddError(
+			fmt.Sprintf("Cannot destroy table %s", state.ID.ValueString()),
+			"deletion_protection is set. Set deletion_protection = false and apply before destroying the table.",
+		)
+		return
 	}
 
-	// Build options map
-	options := make(map[string]interface{})
-	options["compaction_strategy"] = d.Get("compaction_strategy").(string)
+	namespace, name, err := parseTableID(state.ID.ValueString())
+	if err != nil {
+		resp.Diagnostics.AddError("Invalid ID format", err.Error())
+		return
+	}
 
-	if clusteringOrder, ok := d.GetOk("clustering_order"); ok {
-		options["clustering_order"] = clusteringOrder.(map[string]interface{})
+	err = r.meta.client.DeleteTable(ctx, namespace, name)
+	if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
 	}
+}
 
-	err := client.CreateTable(ctx, namespace, name, columns, options)
+// ImportState fills in the key and column definitions from the table metadata,
+// so that a configuration matching the existing table does not plan a replacement.
+func (r *tableResource) ImportState(ctx context.Context, req resource.ImportStateRequest, resp *resource.ImportStateResponse) {
+	namespace, name, err := parseTableID(req.ID)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Invalid ID format", err.Error())
+		return
 	}
 
-	d.SetId(fmt.Sprintf("%s.%s", namespace, name))
+	table, err := r.meta.client.GetTableSchema(ctx, namespace, name)
+	if err != nil {
+		resp.Diagnostics.Append(clientErrorDiagnostics(err)...)
+		return
+	}
 
-	return resourceScalarDBTableRead(ctx, d, m)
+	state := flattenTableDefinition(namespace, name, table)
+	state.ID = types.StringValue(tableID(namespace, name))
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
 }
 
-func resourceScalarDBTableRead(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+// ValidateConfig validates the column and key definitions of the table.
+func (r *tableResource) ValidateConfig(ctx context.Context, req resource.ValidateConfigRequest, resp *resource.ValidateConfigResponse) {
+	var config tableResourceModel
+	resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
 
-	var diags diag.Diagnostics
+	if !allKnown(config.Column, config.PartitionKey, config.ClusteringKey, config.ClusteringOrder) {
+		// Values that are only known after apply are validated by the server.
+		return
+	}
 
-	idParts := strings.Split(d.Id(), ".")
-	if len(idParts) != 2 {
-		return diag.Errorf("Invalid ID format: %s (expected namespace.table)", d.Id())
+	var columns []columnModel
+	resp.Diagnostics.Append(config.Column.ElementsAs(ctx, &columns, false)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	columnNames := make([]string, 0, len(columns))
+	for _, column := range columns {
+		if column.Name.IsUnknown() {
+			return
+		}
+		columnNames = append(columnNames, column.Name.ValueString())
 	}
 
-	namespace := idParts[0]
-	name := idParts[1]
+	for _, e := range validateTableKeys(
+		columnNames,
+		listStrings(config.PartitionKey.Elements()),
+		listStrings(config.ClusteringKey.Elements()),
+		expandOptions(config.ClusteringOrder),
+	) {
+		resp.Diagnostics.AddAttributeError(e.path, "Invalid table definition", e.message)
+	}
+}
 
-	exists, err := client.TableExists(ctx, namespace, name)
-	if err != nil {
-		return diag.FromErr(err)
+// tableKeyError is a problem with the key definition of a table.
+type tableKeyError struct {
+	path    path.Path
+	message string
+}
+
+// validateTableKeys checks that column names are unique, that the partition key is not empty,
+// that the key columns are defined and listed only once, and that clustering orders refer to
+// clustering key columns.
+func validateTableKeys(columnNames, partitionKey, clusteringKey []string, clusteringOrder map[string]string) []tableKeyError {
+	var errs []tableKeyError
+
+	// Column names must be unique
+	columns := make(map[string]bool)
+	for _, columnName := range columnNames {
+		if columns[columnName] {
+			errs = append(errs, tableKeyError{path.Root("column"), fmt.Sprintf("Column %q is defined more than once.", columnName)})
+		}
+		columns[columnName] = true
 	}
 
-	if !exists {
-		d.SetId("")
-		return diags
+	if len(partitionKey) == 0 {
+		errs = append(errs, tableKeyError{path.Root("partition_key"), "At least one partition key column is required."})
 	}
 
-	_, _, err = client.GetTableSchema(ctx, namespace, name)
-	if err != nil {
-		return diag.FromErr(err)
+	// Key columns must be defined in column and appear only once
+	keyColumns := make(map[string]string)
+	for _, key := range []struct {
+		name    string
+		columns []string
+	}{{"partition_key", partitionKey}, {"clustering_key", clusteringKey}} {
+		for i, columnName := range key.columns {
+			p := path.Root(key.name).AtListIndex(i)
+			if !columns[columnName] {
+				errs = append(errs, tableKeyError{p, fmt.Sprintf("Column %q is not defined in column.", columnName)})
+			}
+			if other, ok := keyColumns[columnName]; ok {
+				if other == key.name {
+					errs = append(errs, tableKeyError{p, fmt.Sprintf("Column %q is listed more than once.", columnName)})
+				} else {
+					errs = append(errs, tableKeyError{p, fmt.Sprintf("Column %q is already part of %s.", columnName, other)})
+				}
+				continue
+			}
+			keyColumns[columnName] = key.name
+		}
 	}
 
-	// In a real implementation, we would parse the columns and options
-	// and update the state with the actual values.
-	// For now, we'll just use the values from the configuration.
+	// Clustering orders must refer to clustering key columns
+	for _, columnName := range sortedKeys(clusteringOrder) {
+		p := path.Root("clustering_order").AtMapKey(columnName)
+		if keyColumns[columnName] != "clustering_key" {
+			errs = append(errs, tableKeyError{p, fmt.Sprintf("Column %q is not a clustering key column.", columnName)})
+		}
+		if order := clusteringOrder[columnName]; order != "ASC" && order != "DESC" {
+			errs = append(errs, tableKeyError{p, fmt.Sprintf("Order %q of column %q must be ASC or DESC.", order, columnName)})
+		}
+	}
 
-	d.Set("namespace", namespace)
-	d.Set("name", name)
+	return errs
+}
 
-	return diags
+// ModifyPlan checks the option blocks against the backend, plans the effective creation
+// options of new tables, plans a repair when the metadata is out of sync, and warns about
+// or rejects changes that drop and recreate the table.
+func (r *tableResource) ModifyPlan(ctx context.Context, req resource.ModifyPlanRequest, resp *resource.ModifyPlanResponse) {
+	if req.Plan.Raw.IsNull() {
+		return
+	}
+
+	var plan tableResourceModel
+	resp.Diagnostics.Append(req.Plan.Get(ctx, &plan)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	// The framework does not reliably apply attribute defaults to the elements of a set,
+	// so secondary_index and encrypted are planned from the configured columns here.
+	var configColumns types.Set
+	resp.Diagnostics.Append(req.Config.GetAttribute(ctx, path.Root("column"), &configColumns)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+	if !configColumns.IsNull() && !configColumns.IsUnknown() {
+		if columns, err := columnsWithMetadata(ctx, configColumns, nil); err == nil {
+			plan.Column = columns
+			resp.Diagnostics.Append(resp.Plan.SetAttribute(ctx, path.Root("column"), columns)...)
+		}
+	}
+
+	if r.meta == nil {
+		return
+	}
+
+	set := blocksSet(len(plan.Cassandra), len(plan.Dynamo), len(plan.Cosmos))
+	if err := checkBackendBlocks(r.meta.backend, set, expandOptions(plan.Options)); err != nil {
+		resp.Diagnostics.AddError("Storage options do not match the backend", err.Error())
+		return
+	}
+
+	if req.State.Raw.IsNull() {
+		// Changing the provider defaults does not replace existing tables,
+		// so the effective options are only planned for new ones.
+		var config tableResourceModel
+		resp.Diagnostics.Append(req.Config.Get(ctx, &config)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		if config.optionsKnown() {
+			options := expandTableDefinition(ctx, &plan, r.meta).CreationOptions()
+			plan.EffectiveOptions = stringMapValue(options)
+			plan.setEffectiveOptions(options)
+		}
+		resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
+		return
+	}
+
+	var state tableResourceModel
+	resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if len(resp.RequiresReplace) > 0 {
+		changed := make([]string, 0, len(resp.RequiresReplace))
+		for _, p := range resp.RequiresReplace {
+			changed = append(changed, p.String())
+		}
+		sort.Strings(changed)
+
+		if plan.DeletionProtection.ValueBool() {
+			resp.Diagnostics.AddError(
+				fmt.Sprintf("Cannot replace table %s", state.ID.ValueString()),
+				fmt.Sprintf("The table has deletion_protection set, but changes to %s would drop and recreate it and delete all of its data.",
+					strings.Join(changed, ", ")),
+			)
+			return
+		}
+
+		resp.Diagnostics.AddWarning(
+			fmt.Sprintf("Table %s will be dropped and recreated", state.ID.ValueString()),
+			fmt.Sprintf("Changes to %s force the table to be dropped and recreated. ALL DATA IN THE TABLE WILL BE LOST.",
+				strings.Join(changed, ", ")),
+		)
+		return
+	}
+
+	plan.MetadataInSync = planMetadataInSync(plan.RepairOnDrift, state.MetadataInSync)
+
+	resp.Diagnostics.Append(resp.Plan.Set(ctx, plan)...)
 }
 
-func resourceScalarDBTableUpdate(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	// In ScalarDB, table schema cannot be updated after creation.
-	// So this is a no-op.
-	return resourceScalarDBTableRead(ctx, d, m)
+// optionsKnown reports whether all attributes that contribute to the creation options are known.
+func (m *tableResourceModel) optionsKnown() bool {
+	values := []attr.Value{m.Options}
+	for _, b := range m.Cassandra {
+		values = append(values, b.CompactionStrategy)
+	}
+	return allKnown(append(values, storageValues(m.Dynamo, m.Cosmos)...)...)
 }
 
-func resourceScalarDBTableDelete(ctx context.Context, d *schema.ResourceData, m interface{}) diag.Diagnostics {
-	client := m.(*Client)
+// expandTableDefinition builds the table definition from the resource model.
+// The key columns keep the order in which they are declared, and the creation
+// options are merged over the provider-level defaults.
+func expandTableDefinition(ctx context.Context, m *tableResourceModel, meta *providerMeta) *scalardb.TableDefinition {
+	table := &scalardb.TableDefinition{
+		ClusteringOrder: expandOptions(m.ClusteringOrder),
+	}
 
-	var diags diag.Diagnostics
+	var columns []columnModel
+	m.Column.ElementsAs(ctx, &columns, false)
+	for _, column := range columns {
+		table.Columns = append(table.Columns, scalardb.ColumnDefinition{
+			Name:      column.Name.ValueString(),
+			Type:      column.Type.ValueString(),
+			Encrypted: column.Encrypted.ValueBool(),
+		})
+		if column.SecondaryIndex.ValueBool() {
+			table.SecondaryIndexes = append(table.SecondaryIndexes, column.Name.ValueString())
+		}
+	}
+	// Sets have no meaningful order, so sort the columns for a stable definition
+	sort.Slice(table.Columns, func(i, j int) bool {
+		return table.Columns[i].Name < table.Columns[j].Name
+	})
+	sort.Strings(table.SecondaryIndexes)
+
+	table.PartitionKey = listStrings(m.PartitionKey.Elements())
+	table.ClusteringKey = listStrings(m.ClusteringKey.Elements())
+
+	table.Storage = expandStorageOptions(m.Cassandra, m.Dynamo, m.Cosmos)
+	table.Options = mergeOptions(meta.defaultTableOptions, table.Storage.Map(), expandOptions(m.Options))
+
+	return table
+}
+
+// setEffectiveOptions sets the option attributes that are not configured to the
+// effective creation options.
+func (m *tableResourceModel) setEffectiveOptions(options map[string]string) {
+	m.Cassandra, m.Dynamo, m.Cosmos = withEffectiveStorageOptions(m.Cassandra, m.Dynamo, m.Cosmos, options)
+}
+
+// flattenTableDefinition builds the state of an imported table from its metadata.
+// Only descending clustering orders are recorded, since ASC is the default.
+func flattenTableDefinition(namespace, name string, table *scalardb.TableDefinition) tableResourceModel {
+	columns := make([]attr.Value, 0, len(table.Columns))
+	for _, column := range table.Columns {
+		columns = append(columns, types.ObjectValueMust(columnAttrTypes, map[string]attr.Value{
+			"name":            types.StringValue(column.Name),
+			"type":            types.StringValue(column.Type),
+			"secondary_index": types.BoolValue(slices.Contains(table.SecondaryIndexes, column.Name)),
+			"encrypted":       types.BoolValue(column.Encrypted),
+		}))
+	}
+
+	clusteringOrder := make(map[string]string)
+	for _, colName := range table.ClusteringKey {
+		if order := clusteringOrderOrDefault(table.ClusteringOrder, colName); order != "ASC" {
+			clusteringOrder[colName] = order
+		}
+	}
 
-	idParts := strings.Split(d.Id(), ".")
-	if len(idParts) != 2 {
-		return diag.Errorf("Invalid ID format: %s (expected namespace.table)", d.Id())
+	state := tableResourceModel{
+		Namespace:          types.StringValue(namespace),
+		Name:               types.StringValue(name),
+		PartitionKey:       stringListValue(table.PartitionKey),
+		ClusteringKey:      types.ListNull(types.StringType),
+		Column:             types.SetValueMust(types.ObjectType{AttrTypes: columnAttrTypes}, columns),
+		ClusteringOrder:    types.MapNull(types.StringType),
+		Cassandra:          []cassandraTableModel{},
+		Dynamo:             []dynamoModel{},
+		Cosmos:             []cosmosModel{},
+		Options:            types.MapNull(types.StringType),
+		EffectiveOptions:   types.MapNull(types.StringType),
+		DeletionProtection: types.BoolValue(false),
+		RepairOnDrift:      types.BoolValue(false),
+		MetadataInSync:     types.BoolNull(),
 	}
+	if len(table.ClusteringKey) > 0 {
+		state.ClusteringKey = stringListValue(table.ClusteringKey)
+	}
+	if len(clusteringOrder) > 0 {
+		state.ClusteringOrder = stringMapValue(clusteringOrder)
+	}
+	return state
+}
 
-	namespace := idParts[0]
-	name := idParts[1]
+func (r *tableResource) UpgradeState(ctx context.Context) map[int64]resource.StateUpgrader {
+	var schemaResp resource.SchemaResponse
+	r.Schema(ctx, resource.SchemaRequest{}, &schemaResp)
 
-	err := client.DeleteTable(ctx, namespace, name)
+	return map[int64]resource.StateUpgrader{
+		// Version 0 is the state written by the SDKv2 provider
+		0: {
+			PriorSchema: tableSchemaV0(),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				r.upgradeState(ctx, req, resp, 0)
+			},
+		},
+		// Version 1 has the shape of the SDKv2 provider without the attributes of the
+		// baseline release. Its columns lack the secondary_index and encrypted attributes
+		1: {
+			PriorSchema: tableSchemaV1(),
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				r.upgradeState(ctx, req, resp, 1)
+			},
+		},
+		// Version 2 has the current shape, but its ID does not escape the names
+		2: {
+			PriorSchema: &schemaResp.Schema,
+			StateUpgrader: func(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse) {
+				r.upgradeState(ctx, req, resp, 2)
+			},
+		},
+	}
+}
+
+// upgradeState upgrades state of an older version to the current one. State written by
+// the SDKv2 provider is normalized first. The ID is rebuilt from the names, since IDs of older
+// versions did not escape them. Column attributes missing from older versions are filled
+// from the table metadata, or set to false if the metadata cannot be read.
+func (r *tableResource) upgradeState(ctx context.Context, req resource.UpgradeStateRequest, resp *resource.UpgradeStateResponse, version int64) {
+	var state tableResourceModel
+	if version == 0 {
+		var v0 tableResourceModelV0
+		resp.Diagnostics.Append(req.State.Get(ctx, &v0)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+		resp.Diagnostics.Append(upgradeTableStateV0(&v0)...)
+		state = v0.tableResourceModel
+	} else {
+		resp.Diagnostics.Append(req.State.Get(ctx, &state)...)
+		if resp.Diagnostics.HasError() {
+			return
+		}
+	}
+	state.ID = types.StringValue(tableID(state.Namespace.ValueString(), state.Name.ValueString()))
+
+	if version >= 2 {
+		resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+		return
+	}
+
+	var table *scalardb.TableDefinition
+	if r.meta != nil {
+		var err error
+		table, err = r.meta.client.GetTableSchema(ctx, state.Namespace.ValueString(), state.Name.ValueString())
+		if err != nil && !errors.Is(err, scalardb.ErrNotFound) {
+			resp.Diagnostics.AddWarning(
+				fmt.Sprintf("Could not read the metadata of table %s", state.ID.ValueString()),
+				fmt.Sprintf("secondary_index and encrypted are set to false until the next refresh.\n\n%s", err),
+			)
+		}
+	}
+
+	columns, err := columnsWithMetadata(ctx, state.Column, table)
 	if err != nil {
-		return diag.FromErr(err)
+		resp.Diagnostics.AddError("Failed to upgrade columns", err.Error())
+		return
+	}
+	state.Column = columns
+
+	resp.Diagnostics.Append(resp.State.Set(ctx, state)...)
+}
+
+// columnsWithMetadata converts column blocks of the current or an older version to the
+// current column type. secondary_index and encrypted are taken from the table metadata
+// if it is given, and otherwise kept or set to false.
+func columnsWithMetadata(ctx context.Context, columns types.Set, table *scalardb.TableDefinition) (types.Set, error) {
+	encrypted := make(map[string]bool)
+	if table != nil {
+		for _, column := range table.Columns {
+			encrypted[column.Name] = column.Encrypted
+		}
+	}
+
+	elements := make([]attr.Value, 0, len(columns.Elements()))
+	for _, element := range columns.Elements() {
+		object, ok := element.(types.Object)
+		if !ok {
+			return types.SetNull(types.ObjectType{AttrTypes: columnAttrTypes}), fmt.Errorf("unexpected column value %T", element)
+		}
+		attrs := object.Attributes()
+
+		values := map[string]attr.Value{
+			"name":            attrs["name"],
+			"type":            attrs["type"],
+			"secondary_index": types.BoolValue(false),
+			"encrypted":       types.BoolValue(false),
+		}
+		for _, key := range []string{"secondary_index", "encrypted"} {
+			if v, ok := attrs[key].(types.Bool); ok && !v.IsNull() {
+				values[key] = v
+			}
+		}
+		if name, ok := attrs["name"].(types.String); ok && table != nil {
+			if _, found := encrypted[name.ValueString()]; found {
+				values["secondary_index"] = types.BoolValue(slices.Contains(table.SecondaryIndexes, name.ValueString()))
+				values["encrypted"] = types.BoolValue(encrypted[name.ValueString()])
+			}
+		}
+
+		column, diags := types.ObjectValue(columnAttrTypes, values)
+		if diags.HasError() {
+			return types.SetNull(types.ObjectType{AttrTypes: columnAttrTypes}), fmt.Errorf("failed to convert column: %v", diags)
+		}
+		elements = append(elements, column)
 	}
 
-	d.SetId("")
+	set, diags := types.SetValue(types.ObjectType{AttrTypes: columnAttrTypes}, elements)
+	if diags.HasError() {
+		return types.SetNull(types.ObjectType{AttrTypes: columnAttrTypes}), fmt.Errorf("failed to convert columns: %v", diags)
+	}
+	return set, nil
+}
+
+// tableResourceModelV0 is the state of scalardb_table written by the SDKv2 provider.
+// The first releases had compaction_strategy instead of the cassandra block.
+type tableResourceModelV0 struct {
+	tableResourceModel
+	CompactionStrategy types.String `tfsdk:"compaction_strategy"`
+}
 
+// upgradeTableStateV0 fills in the attributes that SDKv2 state may lack, normalizes
+// empty values the way the framework provider records them, and moves compaction_strategy
+// into the cassandra block. A compaction strategy the block does not accept is dropped
+// with a warning.
+func upgradeTableStateV0(state *tableResourceModelV0) diag.Diagnostics {
+	var diags diag.Diagnostics
+
+	state.DeletionProtection = boolValueOrDefault(state.DeletionProtection, false)
+	state.RepairOnDrift = boolValueOrDefault(state.RepairOnDrift, false)
+	state.Options = nullIfEmpty(state.Options)
+	state.ClusteringOrder = nullIfEmpty(state.ClusteringOrder)
+	if len(state.ClusteringKey.Elements()) == 0 {
+		state.ClusteringKey = types.ListNull(types.StringType)
+	}
+	if state.Cassandra == nil {
+		state.Cassandra = []cassandraTableModel{}
+	}
+	if state.Dynamo == nil {
+		state.Dynamo = []dynamoModel{}
+	}
+	if state.Cosmos == nil {
+		state.Cosmos = []cosmosModel{}
+	}
+
+	v := state.CompactionStrategy.ValueString()
+	if v == "" {
+		return diags
+	}
+	id := tableID(state.Namespace.ValueString(), state.Name.ValueString())
+	if len(state.Cassandra) > 0 {
+		diags.AddWarning(
+			fmt.Sprintf("Ignoring compaction_strategy of table %s", id),
+			"The state has both compaction_strategy and a cassandra block. The cassandra block is kept.",
+		)
+		return diags
+	}
+	strategy, ok := compactionStrategyName(v)
+	if !ok {
+		diags.AddWarning(
+			fmt.Sprintf("Dropping compaction_strategy of table %s", id),
+			fmt.Sprintf("%q is not a compaction strategy of the cassandra block. Valid values are %s.", v, strings.Join(compactionStrategies, ", ")),
+		)
+		return diags
+	}
+	state.Cassandra = []cassandraTableModel{{CompactionStrategy: types.StringValue(strategy)}}
 	return diags
 }
+
+// compactionStrategyName returns the short name ScalarDB accepts for a compaction strategy
+// given by its Cassandra class name, such as SizeTieredCompactionStrategy, or its short name.
+func compactionStrategyName(s string) (string, bool) {
+	if slices.Contains(compactionStrategies, s) {
+		return s, true
+	}
+	switch s[strings.LastIndex(s, ".")+1:] {
+	case "SizeTieredCompactionStrategy":
+		return "STCS", true
+	case "LeveledCompactionStrategy":
+		return "LCS", true
+	case "TimeWindowCompactionStrategy":
+		return "TWCS", true
+	}
+	return "", false
+}
+
+// tableSchemaV0 returns the schema of scalardb_table in the SDKv2 provider. It includes
+// the attribute of the baseline release that the cassandra block replaced.
+func tableSchemaV0() *schema.Schema {
+	s := tableSchemaV1()
+	s.Attributes["compaction_strategy"] = schema.StringAttribute{Optional: true}
+	return s
+}
+
+// tableSchemaV1 returns the schema of scalardb_table in the last SDKv2 releases and the
+// first framework release.
+func tableSchemaV1() *schema.Schema {
+	return &schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"id":                  schema.StringAttribute{Computed: true},
+			"namespace":           schema.StringAttribute{Required: true},
+			"name":                schema.StringAttribute{Required: true},
+			"partition_key":       schema.ListAttribute{ElementType: types.StringType, Required: true},
+			"clustering_key":      schema.ListAttribute{ElementType: types.StringType, Optional: true},
+			"clustering_order":    schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"options":             schema.MapAttribute{ElementType: types.StringType, Optional: true},
+			"effective_options":   schema.MapAttribute{ElementType: types.StringType, Computed: true},
+			"deletion_protection": schema.BoolAttribute{Optional: true},
+			"repair_on_drift":     schema.BoolAttribute{Optional: true},
+			"metadata_in_sync":    schema.BoolAttribute{Computed: true},
+		},
+		Blocks: map[string]schema.Block{
+			"column": schema.SetNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"name": schema.StringAttribute{Required: true},
+						"type": schema.StringAttribute{Required: true},
+					},
+				},
+			},
+			"cassandra": schema.ListNestedBlock{
+				NestedObject: schema.NestedBlockObject{
+					Attributes: map[string]schema.Attribute{
+						"compaction_strategy": schema.StringAttribute{Optional: true},
+					},
+				},
+			},
+			"dynamo": dynamoBlockV0(),
+			"cosmos": cosmosBlockV0(),
+		},
+	}
+}
+
+// tableSchemaMatches reports whether the table schema read from ScalarDB matches
+// the declared columns, keys (including their order) and clustering orders.
+func tableSchemaMatches(declared, actual *scalardb.TableDefinition) bool {
+	if len(declared.Columns) != len(actual.Columns) {
+		return false
+	}
+
+	actualTypes := make(map[string]string)
+	for _, column := range actual.Columns {
+		actualTypes[column.Name] = column.Type
+	}
+	for _, column := range declared.Columns {
+		if actualType, ok := actualTypes[column.Name]; !ok || actualType != column.Type {
+			return false
+		}
+	}
+
+	if !slices.Equal(declared.PartitionKey, actual.PartitionKey) ||
+		!slices.Equal(declared.ClusteringKey, actual.ClusteringKey) {
+		return false
+	}
+
+	for _, colName := range declared.ClusteringKey {
+		if clusteringOrderOrDefault(declared.ClusteringOrder, colName) != clusteringOrderOrDefault(actual.ClusteringOrder, colName) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// clusteringOrderOrDefault returns the clustering order of a column, defaulting to ASC.
+func clusteringOrderOrDefault(orders map[string]string, colName string) string {
+	if order, ok := orders[colName]; ok && order != "" {
+		return order
+	}
+	return "ASC"
+}
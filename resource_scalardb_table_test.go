@@ -0,0 +1,647 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/path"
+	"github.com/hashicorp/terraform-plugin-framework/resource/schema/planmodifier"
+	"github.com/hashicorp/terraform-plugin-framework/tfsdk"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-go/tftypes"
+	"github.com/hashicorp/terraform-plugin-testing/helper/resource"
+	"github.com/hashicorp/terraform-plugin-testing/plancheck"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+)
+
+// usersTable is the live metadata of the table captured in the table state fixtures.
+var usersTable = &scalardb.TableDefinition{
+	Columns: []scalardb.ColumnDefinition{
+		{Name: "user_id", Type: "INT"},
+		{Name: "created_at", Type: "BIGINT"},
+		{Name: "email", Type: "TEXT"},
+		{Name: "secret", Type: "BLOB", Encrypted: true},
+	},
+	PartitionKey:     []string{"user_id"},
+	ClusteringKey:    []string{"created_at"},
+	ClusteringOrder:  map[string]string{"created_at": "DESC"},
+	SecondaryIndexes: []string{"email"},
+}
+
+type columnFlags struct {
+	secondaryIndex bool
+	encrypted      bool
+}
+
+// upgradedTable decodes an upgraded table state and its column flags.
+func upgradedTable(t *testing.T, state tfsdk.State) (tableResourceModel, map[string]columnFlags) {
+	t.Helper()
+	ctx := context.Background()
+
+	var m tableResourceModel
+	if diags := state.Get(ctx, &m); diags.HasError() {
+		t.Fatalf("failed to decode upgraded state: %v", diags)
+	}
+	var columns []columnModel
+	if diags := m.Column.ElementsAs(ctx, &columns, false); diags.HasError() {
+		t.Fatalf("failed to decode columns: %v", diags)
+	}
+	flags := make(map[string]columnFlags)
+	for _, column := range columns {
+		if column.SecondaryIndex.IsNull() || column.Encrypted.IsNull() {
+			t.Errorf("column %s: secondary_index or encrypted is null", column.Name.ValueString())
+		}
+		flags[column.Name.ValueString()] = columnFlags{column.SecondaryIndex.ValueBool(), column.Encrypted.ValueBool()}
+	}
+	return m, flags
+}
+
+func TestTableUpgradeStateV0(t *testing.T) {
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{
+		tables: map[string]*scalardb.TableDefinition{"example_namespace.users": usersTable},
+	}}}
+
+	state, warnings := upgradeTestState(t, r, 0, "scalardb_table_v0.json")
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	m, flags := upgradedTable(t, state)
+
+	if got := m.ID.ValueString(); got != "example_namespace.users" {
+		t.Errorf("id = %q", got)
+	}
+	if !m.Options.IsNull() {
+		t.Errorf("empty options should be null, got %s", m.Options)
+	}
+	if got := listStrings(m.ClusteringKey.Elements()); len(got) != 1 || got[0] != "created_at" {
+		t.Errorf("clustering_key = %v", got)
+	}
+	if got := expandOptions(m.ClusteringOrder); got["created_at"] != "DESC" {
+		t.Errorf("clustering_order = %v", got)
+	}
+	// compaction_strategy moves into the cassandra block by its short name
+	if len(m.Cassandra) != 1 || m.Cassandra[0].CompactionStrategy.ValueString() != "STCS" {
+		t.Errorf("cassandra = %+v", m.Cassandra)
+	}
+	if m.Dynamo == nil || m.Cosmos == nil {
+		t.Error("option blocks should be empty, not null")
+	}
+
+	want := map[string]columnFlags{
+		"user_id":    {},
+		"created_at": {},
+		"email":      {secondaryIndex: true},
+		"secret":     {encrypted: true},
+	}
+	for name, w := range want {
+		if flags[name] != w {
+			t.Errorf("column %s = %+v, want %+v", name, flags[name], w)
+		}
+	}
+}
+
+func TestTableUpgradeStateV0Minimal(t *testing.T) {
+	// State written before the option blocks and deletion protection existed,
+	// for a table that has been dropped since
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{}}}
+
+	state, warnings := upgradeTestState(t, r, 0, "scalardb_table_v0_minimal.json")
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	m, flags := upgradedTable(t, state)
+
+	if m.DeletionProtection.IsNull() || m.DeletionProtection.ValueBool() {
+		t.Errorf("deletion_protection = %s, want false", m.DeletionProtection)
+	}
+	if m.RepairOnDrift.IsNull() || m.RepairOnDrift.ValueBool() {
+		t.Errorf("repair_on_drift = %s, want false", m.RepairOnDrift)
+	}
+	if !m.ClusteringKey.IsNull() {
+		t.Errorf("empty clustering_key should be null, got %s", m.ClusteringKey)
+	}
+	if m.Cassandra == nil || m.Dynamo == nil || m.Cosmos == nil {
+		t.Error("option blocks should be empty, not null")
+	}
+	for name, f := range flags {
+		if f != (columnFlags{}) {
+			t.Errorf("column %s = %+v, want false flags for a missing table", name, f)
+		}
+	}
+}
+
+func TestTableUpgradeStateV0Unmapped(t *testing.T) {
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{}}}
+
+	state, warnings := upgradeTestState(t, r, 0, "scalardb_table_v0_unmapped.json")
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning about compaction_strategy, got %v", warnings)
+	}
+	m, _ := upgradedTable(t, state)
+	if len(m.Cassandra) != 0 {
+		t.Errorf("cassandra = %+v, want none", m.Cassandra)
+	}
+	if !m.ClusteringOrder.IsNull() {
+		t.Errorf("empty clustering_order should be null, got %s", m.ClusteringOrder)
+	}
+}
+
+func TestTableUpgradeStateV0MetadataUnavailable(t *testing.T) {
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{
+		err: errors.New("connection refused"),
+	}}}
+
+	state, warnings := upgradeTestState(t, r, 0, "scalardb_table_v0.json")
+	if len(warnings) != 1 {
+		t.Errorf("expected a warning about the metadata, got %v", warnings)
+	}
+	_, flags := upgradedTable(t, state)
+	for name, f := range flags {
+		if f != (columnFlags{}) {
+			t.Errorf("column %s = %+v, want false flags", name, f)
+		}
+	}
+}
+
+func TestTableUpgradeStateV0Unconfigured(t *testing.T) {
+	state, _ := upgradeTestState(t, &tableResource{}, 0, "scalardb_table_v0.json")
+	_, flags := upgradedTable(t, state)
+	if len(flags) != 4 {
+		t.Errorf("expected 4 columns, got %d", len(flags))
+	}
+}
+
+func TestTableUpgradeStateV1(t *testing.T) {
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{
+		tables: map[string]*scalardb.TableDefinition{"example_namespace.users": usersTable},
+	}}}
+
+	state, warnings := upgradeTestState(t, r, 1, "scalardb_table_v1.json")
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	m, flags := upgradedTable(t, state)
+
+	if !m.DeletionProtection.ValueBool() {
+		t.Error("deletion_protection should be kept")
+	}
+	if len(m.Dynamo) != 1 || m.Dynamo[0].RU.ValueInt64() != 20 {
+		t.Errorf("dynamo = %+v", m.Dynamo)
+	}
+	if got := expandOptions(m.EffectiveOptions); got["ru"] != "20" {
+		t.Errorf("effective_options = %v", got)
+	}
+	if flags["email"] != (columnFlags{secondaryIndex: true}) || flags["secret"] != (columnFlags{encrypted: true}) {
+		t.Errorf("columns = %+v", flags)
+	}
+}
+
+func TestTableUpgradeStateV2(t *testing.T) {
+	// The upgrade only rebuilds the ID, so the metadata is not read
+	r := &tableResource{meta: &providerMeta{client: &fakeAdminClient{
+		err: errors.New("unexpected call"),
+	}}}
+
+	state, warnings := upgradeTestState(t, r, 2, "scalardb_table_v2.json")
+	if len(warnings) > 0 {
+		t.Errorf("unexpected warnings: %v", warnings)
+	}
+	m, flags := upgradedTable(t, state)
+
+	if got, want := m.ID.ValueString(), `example\.app.users\.v2`; got != want {
+		t.Errorf("id = %q, want %q", got, want)
+	}
+	namespace, name, err := parseTableID(m.ID.ValueString())
+	if err != nil || namespace != "example.app" || name != "users.v2" {
+		t.Errorf("parseTableID(%q) = %q, %q, %v", m.ID.ValueString(), namespace, name, err)
+	}
+	if flags["email"] != (columnFlags{secondaryIndex: true}) || flags["secret"] != (columnFlags{encrypted: true}) {
+		t.Errorf("columns = %+v", flags)
+	}
+}
+
+func TestTableReadMetadataInSync(t *testing.T) {
+	drifted := *usersTable
+	drifted.Columns = append(slices.Clone(usersTable.Columns), scalardb.ColumnDefinition{Name: "extra", Type: "TEXT"})
+
+	tests := []struct {
+		name       string
+		table      *scalardb.TableDefinition
+		wantExists bool
+		wantInSync bool
+	}{
+		{name: "in sync", table: usersTable, wantExists: true, wantInSync: true},
+		{name: "drifted", table: &drifted, wantExists: true},
+		// A dropped table is removed from the state to be created again, not repaired
+		{name: "missing"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := &fakeAdminClient{tables: map[string]*scalardb.TableDefinition{}}
+			if tt.table != nil {
+				client.tables["app.users"] = tt.table
+			}
+			r := &tableResource{meta: &providerMeta{client: client}}
+
+			state := flattenTableDefinition("app", "users", usersTable)
+			state.ID = types.StringValue(tableID("app", "users"))
+			state.RepairOnDrift = types.BoolValue(true)
+
+			_, exists, err := r.read(context.Background(), &state)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if exists != tt.wantExists {
+				t.Fatalf("exists = %t, want %t", exists, tt.wantExists)
+			}
+			if !exists {
+				return
+			}
+			if got := state.MetadataInSync; !got.Equal(types.BoolValue(tt.wantInSync)) {
+				t.Errorf("metadata_in_sync = %s, want %t", got, tt.wantInSync)
+			}
+			// With repair_on_drift, the metadata is planned to be in sync after the update
+			if got := planMetadataInSync(state.RepairOnDrift, state.MetadataInSync); !got.Equal(types.BoolValue(true)) {
+				t.Errorf("planned metadata_in_sync = %s, want true", got)
+			}
+			if got := planMetadataInSync(types.BoolValue(false), state.MetadataInSync); !got.Equal(state.MetadataInSync) {
+				t.Errorf("planned metadata_in_sync without repair_on_drift = %s, want %s", got, state.MetadataInSync)
+			}
+		})
+	}
+}
+
+func TestValidateTableKeys(t *testing.T) {
+	tests := []struct {
+		name            string
+		columns         []string
+		partitionKey    []string
+		clusteringKey   []string
+		clusteringOrder map[string]string
+		want            []path.Path
+	}{
+		{
+			name:            "valid",
+			columns:         []string{"id", "ts"},
+			partitionKey:    []string{"id"},
+			clusteringKey:   []string{"ts"},
+			clusteringOrder: map[string]string{"ts": "DESC"},
+		},
+		{
+			name:    "empty partition key",
+			columns: []string{"id"},
+			want:    []path.Path{path.Root("partition_key")},
+		},
+		{
+			name:          "undefined and repeated key columns",
+			columns:       []string{"id", "ts"},
+			partitionKey:  []string{"id", "missing"},
+			clusteringKey: []string{"ts", "id"},
+			want: []path.Path{
+				path.Root("partition_key").AtListIndex(1),
+				path.Root("clustering_key").AtListIndex(1),
+			},
+		},
+		{
+			name:            "clustering order",
+			columns:         []string{"id", "ts"},
+			partitionKey:    []string{"id"},
+			clusteringKey:   []string{"ts"},
+			clusteringOrder: map[string]string{"id": "ASC", "ts": "DOWN"},
+			want: []path.Path{
+				path.Root("clustering_order").AtMapKey("id"),
+				path.Root("clustering_order").AtMapKey("ts"),
+			},
+		},
+		{
+			name:         "duplicate column",
+			columns:      []string{"id", "id"},
+			partitionKey: []string{"id"},
+			want:         []path.Path{path.Root("column")},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []path.Path
+			for _, e := range validateTableKeys(tt.columns, tt.partitionKey, tt.clusteringKey, tt.clusteringOrder) {
+				got = append(got, e.path)
+			}
+			if !slices.EqualFunc(got, tt.want, path.Path.Equal) {
+				t.Errorf("got errors at %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRequiresReplaceIfClusteringOrderChanged(t *testing.T) {
+	orders := func(m map[string]string) types.Map {
+		if m == nil {
+			return types.MapNull(types.StringType)
+		}
+		return stringMapValue(m)
+	}
+	tests := []struct {
+		name  string
+		state map[string]string
+		plan  map[string]string
+		want  bool
+	}{
+		// An imported table records no order for ascending columns
+		{name: "explicit ASC", plan: map[string]string{"ts": "ASC"}},
+		{name: "ASC removed", state: map[string]string{"ts": "ASC"}, plan: map[string]string{}},
+		{name: "DESC added", state: map[string]string{"ts": "ASC"}, plan: map[string]string{"ts": "DESC"}, want: true},
+		{name: "DESC removed", state: map[string]string{"ts": "DESC"}, want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := planmodifier.MapRequest{
+				Path:       path.Root("clustering_order"),
+				State:      tfsdk.State{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})},
+				Plan:       tfsdk.Plan{Raw: tftypes.NewValue(tftypes.Object{}, map[string]tftypes.Value{})},
+				StateValue: orders(tt.state),
+				PlanValue:  orders(tt.plan),
+			}
+			resp := planmodifier.MapResponse{PlanValue: req.PlanValue}
+			requiresReplaceIfClusteringOrderChanged().PlanModifyMap(context.Background(), req, &resp)
+			if resp.RequiresReplace != tt.want {
+				t.Errorf("RequiresReplace = %t, want %t", resp.RequiresReplace, tt.want)
+			}
+		})
+	}
+}
+
+func TestAccTable(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+
+	config := func(emailIndex bool) string {
+		return testAccProviderConfig(server, fmt.Sprintf(`
+resource "scalardb_namespace" "test" {
+  name = "acc"
+}
+
+resource "scalardb_table" "test" {
+  namespace        = scalardb_namespace.test.name
+  name             = "users"
+  partition_key    = ["user_id"]
+  clustering_key   = ["created_at"]
+  clustering_order = { created_at = "DESC" }
+
+  column {
+    name = "user_id"
+    type = "INT"
+  }
+
+  column {
+    name = "created_at"
+    type = "BIGINT"
+  }
+
+  column {
+    name            = "email"
+    type            = "TEXT"
+    secondary_index = %t
+  }
+
+  column {
+    name      = "secret"
+    type      = "BLOB"
+    encrypted = true
+  }
+}
+`, emailIndex))
+	}
+
+	indexes := func(want ...string) func(*pb.TableMetadata) error {
+		return func(m *pb.TableMetadata) error {
+			if !slices.Equal(m.SecondaryIndexColumnNames, want) {
+				return fmt.Errorf("secondary indexes: got %v, want %v", m.SecondaryIndexColumnNames, want)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy: resource.ComposeAggregateTestCheckFunc(
+			testAccCheckTableExists(server, "acc", "users", false),
+			testAccCheckNamespaceExists(server, "acc", false),
+		),
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: config(false),
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTableExists(server, "acc", "users", true),
+					testAccCheckTableMetadata(server, "acc", "users", func(m *pb.TableMetadata) error {
+						if m.ClusteringOrders["created_at"] != pb.ClusteringOrder_CLUSTERING_ORDER_DESC {
+							return fmt.Errorf("clustering order of created_at: got %s", m.ClusteringOrders["created_at"])
+						}
+						if !slices.Equal(m.EncryptedColumns, []string{"secret"}) {
+							return fmt.Errorf("encrypted columns: got %v", m.EncryptedColumns)
+						}
+						return nil
+					}),
+					resource.TestCheckResourceAttr("scalardb_table.test", "id", "acc.users"),
+					resource.TestCheckResourceAttr("scalardb_table.test", "column.#", "4"),
+					resource.TestCheckTypeSetElemNestedAttrs("scalardb_table.test", "column.*", map[string]string{
+						"name":      "secret",
+						"encrypted": "true",
+					}),
+				),
+			},
+			// Import
+			{
+				ResourceName:            "scalardb_table.test",
+				ImportState:             true,
+				ImportStateVerify:       true,
+				ImportStateVerifyIgnore: []string{"effective_options"},
+			},
+			// Update: secondary indexes are created in place
+			{
+				Config: config(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_table.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckTableMetadata(server, "acc", "users", indexes("email")),
+			},
+			// Drift: an index dropped outside of Terraform is created again
+			{
+				PreConfig: func() {
+					_, err := server.DropIndex(ctx, &pb.DropIndexRequest{NamespaceName: "acc", TableName: "users", ColumnName: "email"})
+					testAccMust(t, err)
+				},
+				Config: config(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_table.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: testAccCheckTableMetadata(server, "acc", "users", indexes("email")),
+			},
+			// Drift: a table dropped outside of Terraform is created again
+			{
+				PreConfig: func() {
+					_, err := server.DropTable(ctx, &pb.DropTableRequest{NamespaceName: "acc", TableName: "users"})
+					testAccMust(t, err)
+				},
+				Config: config(true),
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_table.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckTableExists(server, "acc", "users", true),
+			},
+		},
+	})
+}
+
+func TestAccTableRepairOnDrift(t *testing.T) {
+	server := testAccMockServer(t)
+	ctx := context.Background()
+
+	config := testAccProviderConfig(server, `
+resource "scalardb_namespace" "test" {
+  name = "acc"
+}
+
+resource "scalardb_table" "test" {
+  namespace       = scalardb_namespace.test.name
+  name            = "events"
+  partition_key   = ["id"]
+  repair_on_drift = true
+
+  column {
+    name = "id"
+    type = "INT"
+  }
+
+  column {
+    name = "payload"
+    type = "TEXT"
+  }
+}
+`)
+
+	columns := func(want ...string) func(*pb.TableMetadata) error {
+		return func(m *pb.TableMetadata) error {
+			if got := sortedKeys(m.Columns); !slices.Equal(got, want) {
+				return fmt.Errorf("columns: got %v, want %v", got, want)
+			}
+			return nil
+		}
+	}
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		Steps: []resource.TestStep{
+			// Create
+			{
+				Config: config,
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTableMetadata(server, "acc", "events", columns("id", "payload")),
+					resource.TestCheckResourceAttr("scalardb_table.test", "metadata_in_sync", "true"),
+				),
+			},
+			// Drift: metadata changed outside of Terraform is repaired in place
+			{
+				PreConfig: func() {
+					_, err := server.AddNewColumnToTable(ctx, &pb.AddNewColumnToTableRequest{
+						NamespaceName:  "acc",
+						TableName:      "events",
+						ColumnName:     "extra",
+						ColumnDataType: pb.DataType_DATA_TYPE_TEXT,
+					})
+					testAccMust(t, err)
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_table.test", plancheck.ResourceActionUpdate),
+					},
+				},
+				Check: resource.ComposeAggregateTestCheckFunc(
+					testAccCheckTableMetadata(server, "acc", "events", columns("id", "payload")),
+					resource.TestCheckResourceAttr("scalardb_table.test", "metadata_in_sync", "true"),
+				),
+			},
+			// Drift: a dropped table is created again rather than repaired
+			{
+				PreConfig: func() {
+					_, err := server.DropTable(ctx, &pb.DropTableRequest{NamespaceName: "acc", TableName: "events"})
+					testAccMust(t, err)
+				},
+				Config: config,
+				ConfigPlanChecks: resource.ConfigPlanChecks{
+					PreApply: []plancheck.PlanCheck{
+						plancheck.ExpectResourceAction("scalardb_table.test", plancheck.ResourceActionCreate),
+					},
+				},
+				Check: testAccCheckTableMetadata(server, "acc", "events", columns("id", "payload")),
+			},
+		},
+	})
+}
+
+func TestAccTablePermissionDenied(t *testing.T) {
+	// The provider sends the username as the auth token.
+	server := testAccMockServer(t, mock.RequireAuth(), mock.WithToken("alice", "alice"))
+	ctx := context.Background()
+	_, err := server.CreateUser(ctx, &pb.CreateUserRequest{Username: "alice"})
+	testAccMust(t, err)
+	_, err = server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "acc"})
+	testAccMust(t, err)
+
+	config := fmt.Sprintf(`
+provider "scalardb" {
+  host     = "127.0.0.1"
+  port     = %d
+  username = "alice"
+  password = "secret"
+}
+
+resource "scalardb_table" "test" {
+  namespace     = "acc"
+  name          = "users"
+  partition_key = ["user_id"]
+
+  column {
+    name = "user_id"
+    type = "INT"
+  }
+}
+`, server.Port())
+
+	resource.Test(t, resource.TestCase{
+		ProtoV6ProviderFactories: testAccProtoV6ProviderFactories,
+		CheckDestroy:             testAccCheckTableExists(server, "acc", "users", false),
+		Steps: []resource.TestStep{
+			{
+				Config:      config,
+				ExpectError: regexp.MustCompile(`(?s)Permission denied: failed to create table.*needs the CREATE privilege`),
+			},
+			{
+				PreConfig: func() {
+					_, err := server.Grant(ctx, &pb.GrantRequest{
+						Username:      "alice",
+						NamespaceName: "acc",
+						Privileges:    []pb.Privilege{pb.Privilege_PRIVILEGE_CREATE, pb.Privilege_PRIVILEGE_DROP},
+					})
+					testAccMust(t, err)
+				},
+				Config: config,
+				Check:  testAccCheckTableExists(server, "acc", "users", true),
+			},
+		},
+	})
+}
@@ -0,0 +1,304 @@
+package scalardb
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+)
+
+// recordingAdminServer records the metadata and request header of the last request.
+type recordingAdminServer struct {
+	pb.UnimplementedDistributedTransactionAdminServer
+	md     metadata.MD
+	header *pb.RequestHeader
+}
+
+func (s *recordingAdminServer) NamespaceExists(ctx context.Context, req *pb.NamespaceExistsRequest) (*pb.NamespaceExistsResponse, error) {
+	s.md, _ = metadata.FromIncomingContext(ctx)
+	s.header = req.RequestHeader
+	return &pb.NamespaceExistsResponse{}, nil
+}
+
+func TestClientHeaders(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	recorder := &recordingAdminServer{}
+	server := grpc.NewServer()
+	pb.RegisterDistributedTransactionAdminServer(server, recorder)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+
+	client := NewClient("127.0.0.1", listener.Addr().(*net.TCPAddr).Port, "", "")
+	client.HopLimit = 3
+	client.Metadata = map[string]string{"x-route": "blue", "X-Team": "data"}
+	client.UserAgent = "terraform-provider-scalardb/test"
+	t.Cleanup(func() { client.Close() })
+
+	if _, err := client.NamespaceExists(context.Background(), "ns"); err != nil {
+		t.Fatal(err)
+	}
+	if got := recorder.header.GetHopLimit(); got != 3 {
+		t.Errorf("got hop limit %d, want 3", got)
+	}
+	for key, want := range map[string]string{"x-route": "blue", "x-team": "data"} {
+		if got := recorder.md.Get(key); len(got) != 1 || got[0] != want {
+			t.Errorf("got %s %v, want %s", key, got, want)
+		}
+	}
+	if got := recorder.md.Get("user-agent"); len(got) != 1 || !strings.HasPrefix(got[0], "terraform-provider-scalardb/test ") {
+		t.Errorf("got user agent %v", got)
+	}
+}
+
+// listenMock starts a mock server on an ephemeral port.
+func listenMock(t *testing.T, server *mock.Server) *mock.Instance {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	instance := server.Serve(listener)
+	t.Cleanup(instance.Stop)
+	return instance
+}
+
+func TestClientFailover(t *testing.T) {
+	// Two nodes of one cluster share its state.
+	cluster := mock.NewServer(mock.WithLogger(nil))
+	nodes := []*mock.Instance{listenMock(t, cluster), listenMock(t, cluster)}
+
+	for _, policy := range LoadBalancingPolicies {
+		t.Run(policy, func(t *testing.T) {
+			client := NewClient("", 0, "", "")
+			client.LoadBalancing = policy
+			for _, node := range nodes {
+				client.Endpoints = append(client.Endpoints, node.Addr().String())
+			}
+			t.Cleanup(func() { client.Close() })
+
+			ctx := context.Background()
+			if err := client.CreateNamespace(ctx, "ns_"+policy, nil); err != nil {
+				t.Fatal(err)
+			}
+			if policy == LoadBalancingPickFirst {
+				// Fail over from the first node, which pick_first is connected to.
+				nodes[0].Stop()
+			} else {
+				nodes[1].Stop()
+			}
+			for i := 0; i < 10; i++ {
+				exists, err := client.NamespaceExists(ctx, "ns_"+policy)
+				if err != nil {
+					t.Fatalf("request %d after a node stopped: %v", i, err)
+				}
+				if !exists {
+					t.Fatalf("request %d after a node stopped: the namespace does not exist", i)
+				}
+			}
+		})
+		nodes = []*mock.Instance{listenMock(t, cluster), listenMock(t, cluster)}
+	}
+}
+
+func TestClientHealthCheck(t *testing.T) {
+	// The namespace exists only on the healthy node.
+	unhealthy := listenMock(t, mock.NewServer(mock.WithLogger(nil)))
+	healthy := listenMock(t, mock.NewServer(mock.WithLogger(nil)))
+	if _, err := healthy.CreateNamespace(context.Background(), &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	unhealthy.SetServing(false)
+
+	client := NewClient("", 0, "", "")
+	client.Endpoints = []string{unhealthy.Addr().String(), healthy.Addr().String()}
+	t.Cleanup(func() { client.Close() })
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	for i := 0; i < 20; i++ {
+		exists, err := client.NamespaceExists(ctx, "ns")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !exists {
+			t.Fatalf("request %d was sent to a node that is not serving", i)
+		}
+	}
+}
+
+func TestEndpointAddress(t *testing.T) {
+	tests := []struct {
+		endpoint string
+		want     string
+		wantErr  bool
+	}{
+		{endpoint: "node1", want: "node1:60051"},
+		{endpoint: "node1:60052", want: "node1:60052"},
+		{endpoint: "10.0.0.1", want: "10.0.0.1:60051"},
+		{endpoint: "[::1]:60052", want: "[::1]:60052"},
+		{endpoint: "::1", want: "[::1]:60051"},
+		{endpoint: "node1:http", wantErr: true},
+		{endpoint: ":60052", wantErr: true},
+		{endpoint: "", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := EndpointAddress(tt.endpoint, 60051)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("EndpointAddress(%q) = %q, %v; want %q, error %t", tt.endpoint, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestClientRetryLostResponse(t *testing.T) {
+	// unavailable loses the response like mock.LostResponse, but with a status the
+	// retry policy retries on.
+	unavailable := mock.LostResponse()
+	unavailable.Code = codes.Unavailable
+
+	table := &TableDefinition{
+		Columns:          []ColumnDefinition{{Name: "id", Type: "INT"}, {Name: "note", Type: "TEXT"}, {Name: "memo", Type: "TEXT"}},
+		PartitionKey:     []string{"id"},
+		SecondaryIndexes: []string{"note"},
+	}
+	tests := []struct {
+		name      string
+		method    string
+		fault     mock.Fault
+		call      func(ctx context.Context, client *Client) error
+		wantOK    bool
+		wantErr   error
+		wantCalls int
+	}{
+		{
+			name:   "add column after deadline",
+			method: "AddNewColumnToTable",
+			fault:  mock.LostResponse(),
+			call: func(ctx context.Context, client *Client) error {
+				return client.AddNewColumnToTable(ctx, "ns", "tbl", ColumnDefinition{Name: "value", Type: "TEXT"})
+			},
+			wantCalls: 1,
+		},
+		{
+			name:   "add column after unavailable",
+			method: "AddNewColumnToTable",
+			fault:  unavailable,
+			call: func(ctx context.Context, client *Client) error {
+				return client.AddNewColumnToTable(ctx, "ns", "tbl", ColumnDefinition{Name: "value", Type: "TEXT"})
+			},
+			wantErr:   ErrUnavailable,
+			wantCalls: 1,
+		},
+		{
+			name:   "create table after unavailable",
+			method: "CreateTable",
+			fault:  unavailable,
+			call: func(ctx context.Context, client *Client) error {
+				return client.CreateTable(ctx, "ns", "other", table)
+			},
+			wantOK:    true,
+			wantCalls: 2,
+		},
+		{
+			name:   "create index after unavailable",
+			method: "CreateIndex",
+			fault:  unavailable,
+			call: func(ctx context.Context, client *Client) error {
+				return client.CreateIndex(ctx, "ns", "tbl", "memo", nil)
+			},
+			wantOK:    true,
+			wantCalls: 2,
+		},
+		{
+			name:   "drop index after unavailable",
+			method: "DropIndex",
+			fault:  unavailable,
+			call: func(ctx context.Context, client *Client) error {
+				return client.DropIndex(ctx, "ns", "tbl", "note")
+			},
+			wantOK:    true,
+			wantCalls: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := mock.NewServer(mock.WithLogger(nil))
+			instance := listenMock(t, server)
+			client := NewClient("127.0.0.1", instance.Port(), "", "")
+			t.Cleanup(func() { client.Close() })
+
+			ctx := context.Background()
+			if err := client.CreateNamespace(ctx, "ns", nil); err != nil {
+				t.Fatal(err)
+			}
+			if err := client.CreateTable(ctx, "ns", "tbl", table); err != nil {
+				t.Fatal(err)
+			}
+			before := server.Calls(tt.method)
+			server.InjectFault(tt.method, tt.fault)
+
+			err := tt.call(ctx, client)
+			switch {
+			case tt.wantOK && err != nil:
+				t.Errorf("got error %v, want the retry to succeed", err)
+			case !tt.wantOK && err == nil:
+				t.Error("got no error, want the error of the lost response")
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("got error %v, want %v", err, tt.wantErr)
+			}
+			// A retry of a non-idempotent RPC would fail, although the first attempt was applied.
+			if got := server.Calls(tt.method) - before; got != tt.wantCalls {
+				t.Errorf("got %d calls, want %d", got, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestClientConcurrentConnect(t *testing.T) {
+	// Run with -race: the first requests of a client connect it concurrently.
+	node := listenMock(t, mock.NewServer(mock.WithLogger(nil)))
+	client := NewClient("127.0.0.1", node.Port(), "", "")
+	t.Cleanup(func() { client.Close() })
+
+	ctx := context.Background()
+	start := make(chan struct{})
+	errs := make(chan error, 16)
+	var wg sync.WaitGroup
+	for i := range cap(errs) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			if i%2 == 0 {
+				_, err := client.NamespaceExists(ctx, "ns")
+				errs <- err
+				return
+			}
+			errs <- client.CreateNamespace(ctx, fmt.Sprintf("ns%d", i), nil)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Error(err)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package scalardb
+
+import (
+	"sort"
+	"strings"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+// convertDataType converts a string data type to a pb.DataType.
+func convertDataType(dataType string) pb.DataType {
+	switch dataType {
+	case "BOOLEAN":
+		return pb.DataType_DATA_TYPE_BOOLEAN
+	case "INT":
+		return pb.DataType_DATA_TYPE_INT
+	case "BIGINT":
+		return pb.DataType_DATA_TYPE_BIGINT
+	case "FLOAT":
+		return pb.DataType_DATA_TYPE_FLOAT
+	case "DOUBLE":
+		return pb.DataType_DATA_TYPE_DOUBLE
+	case "TEXT":
+		return pb.DataType_DATA_TYPE_TEXT
+	case "BLOB":
+		return pb.DataType_DATA_TYPE_BLOB
+	case "DATE":
+		return pb.DataType_DATA_TYPE_DATE
+	case "TIME":
+		return pb.DataType_DATA_TYPE_TIME
+	case "TIMESTAMP":
+		return pb.DataType_DATA_TYPE_TIMESTAMP
+	case "TIMESTAMPTZ":
+		return pb.DataType_DATA_TYPE_TIMESTAMPTZ
+	default:
+		return pb.DataType_DATA_TYPE_TEXT // Default to TEXT
+	}
+}
+
+// convertDataTypeToString converts a pb.DataType to the string data type used in the configuration.
+func convertDataTypeToString(dataType pb.DataType) string {
+	return strings.TrimPrefix(dataType.String(), "DATA_TYPE_")
+}
+
+// convertClusteringOrder converts a string clustering order to a pb.ClusteringOrder.
+func convertClusteringOrder(order string) pb.ClusteringOrder {
+	switch order {
+	case "ASC":
+		return pb.ClusteringOrder_CLUSTERING_ORDER_ASC
+	case "DESC":
+		return pb.ClusteringOrder_CLUSTERING_ORDER_DESC
+	default:
+		return pb.ClusteringOrder_CLUSTERING_ORDER_ASC // Default to ASC
+	}
+}
+
+// buildTableMetadata builds a pb.TableMetadata from the given table definition.
+// The key column order of the definition is preserved.
+func buildTableMetadata(table *TableDefinition) *pb.TableMetadata {
+	tableMetadata := &pb.TableMetadata{
+		Columns:                   make(map[string]pb.DataType),
+		PartitionKeyColumnNames:   append([]string{}, table.PartitionKey...),
+		ClusteringKeyColumnNames:  append([]string{}, table.ClusteringKey...),
+		ClusteringOrders:          make(map[string]pb.ClusteringOrder),
+		SecondaryIndexColumnNames: append([]string{}, table.SecondaryIndexes...),
+		EncryptedColumns:          []string{},
+	}
+
+	for _, column := range table.Columns {
+		tableMetadata.Columns[column.Name] = convertDataType(column.Type)
+		if column.Encrypted {
+			tableMetadata.EncryptedColumns = append(tableMetadata.EncryptedColumns, column.Name)
+		}
+	}
+
+	for _, colName := range table.ClusteringKey {
+		tableMetadata.ClusteringOrders[colName] = convertClusteringOrder(table.ClusteringOrder[colName])
+	}
+
+	return tableMetadata
+}
+
+// flattenTableMetadata converts a pb.TableMetadata to a table definition.
+// Key columns come first in key order, followed by the other columns sorted by name.
+func flattenTableMetadata(tableMetadata *pb.TableMetadata) *TableDefinition {
+	table := &TableDefinition{
+		PartitionKey:     append([]string{}, tableMetadata.PartitionKeyColumnNames...),
+		ClusteringKey:    append([]string{}, tableMetadata.ClusteringKeyColumnNames...),
+		ClusteringOrder:  make(map[string]string),
+		SecondaryIndexes: append([]string{}, tableMetadata.SecondaryIndexColumnNames...),
+		Options:          make(map[string]string),
+	}
+
+	encrypted := make(map[string]bool)
+	for _, colName := range tableMetadata.EncryptedColumns {
+		encrypted[colName] = true
+	}
+
+	added := make(map[string]bool)
+	addColumn := func(colName string) {
+		dataType, ok := tableMetadata.Columns[colName]
+		if !ok || added[colName] {
+			return
+		}
+		table.Columns = append(table.Columns, ColumnDefinition{
+			Name:      colName,
+			Type:      convertDataTypeToString(dataType),
+			Encrypted: encrypted[colName],
+		})
+		added[colName] = true
+	}
+
+	for _, colName := range table.PartitionKey {
+		addColumn(colName)
+	}
+	for _, colName := range table.ClusteringKey {
+		addColumn(colName)
+	}
+	others := make([]string, 0, len(tableMetadata.Columns))
+	for colName := range tableMetadata.Columns {
+		others = append(others, colName)
+	}
+	sort.Strings(others)
+	for _, colName := range others {
+		addColumn(colName)
+	}
+
+	for colName, order := range tableMetadata.ClusteringOrders {
+		table.ClusteringOrder[colName] = convertClusteringOrderToString(order)
+	}
+
+	return table
+}
+
+// convertClusteringOrderToString converts a pb.ClusteringOrder to the string clustering order used in the configuration.
+func convertClusteringOrderToString(order pb.ClusteringOrder) string {
+	return strings.TrimPrefix(order.String(), "CLUSTERING_ORDER_")
+}
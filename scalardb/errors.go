@@ -0,0 +1,91 @@
+package scalardb
+
+import (
+	"errors"
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Error kinds derived from the gRPC status of a failed RPC.
+// Use errors.Is to check the kind of an error returned by Client.
+var (
+	ErrNotFound         = errors.New("not found")
+	ErrAlreadyExists    = errors.New("already exists")
+	ErrPermissionDenied = errors.New("permission denied")
+	ErrUnavailable      = errors.New("unavailable")
+	ErrInvalidArgument  = errors.New("invalid argument")
+)
+
+// Error is returned by Client when a ScalarDB RPC fails.
+type Error struct {
+	// Op is the failed operation, e.g. "create table".
+	Op string
+	// Privilege is the ScalarDB privilege the operation requires, if any.
+	Privilege string
+	// Code is the gRPC status code returned by the server.
+	Code codes.Code
+	// Message is the status message returned by the server.
+	Message string
+	// Reason is the reason from the google.rpc.ErrorInfo status detail, if any.
+	Reason string
+
+	kind error
+	err  error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	msg := fmt.Sprintf("failed to %s: %s: %s", e.Op, e.Code, e.Message)
+	if e.Reason != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Reason)
+	}
+	return msg
+}
+
+// Unwrap returns the error kind and the underlying gRPC error.
+func (e *Error) Unwrap() []error {
+	if e.kind == nil {
+		return []error{e.err}
+	}
+	return []error{e.kind, e.err}
+}
+
+// newError classifies the error of a failed RPC by its gRPC status.
+func newError(op, privilege string, err error) error {
+	st, ok := status.FromError(err)
+	if !ok {
+		return fmt.Errorf("failed to %s: %w", op, err)
+	}
+
+	e := &Error{
+		Op:        op,
+		Privilege: privilege,
+		Code:      st.Code(),
+		Message:   st.Message(),
+		err:       err,
+	}
+
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.ErrorInfo); ok {
+			e.Reason = info.Reason
+		}
+	}
+
+	switch st.Code() {
+	case codes.NotFound:
+		e.kind = ErrNotFound
+	case codes.AlreadyExists:
+		e.kind = ErrAlreadyExists
+	case codes.PermissionDenied, codes.Unauthenticated:
+		e.kind = ErrPermissionDenied
+	case codes.Unavailable:
+		e.kind = ErrUnavailable
+	case codes.InvalidArgument:
+		e.kind = ErrInvalidArgument
+	}
+
+	return e
+}
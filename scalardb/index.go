@@ -0,0 +1,54 @@
+package scalardb
+
+import (
+	"context"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+// CreateIndex creates a secondary index on a column of a table in ScalarDB.
+func (c *Client) CreateIndex(ctx context.Context, namespace, table, column string, options map[string]string) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.CreateIndexRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     table,
+		ColumnName:    column,
+		Options:       options,
+		IfNotExists:   true,
+	}
+
+	_, err = admin.CreateIndex(ctx, req)
+	if err != nil {
+		return newError("create index", "CREATE", err)
+	}
+
+	return nil
+}
+
+// DropIndex drops a secondary index on a column of a table in ScalarDB.
+func (c *Client) DropIndex(ctx context.Context, namespace, table, column string) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.DropIndexRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     table,
+		ColumnName:    column,
+		IfExists:      true,
+	}
+
+	_, err = admin.DropIndex(ctx, req)
+	if err != nil {
+		return newError("drop index", "DROP", err)
+	}
+
+	return nil
+}
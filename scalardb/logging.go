@@ -0,0 +1,106 @@
+package scalardb
+
+import (
+	"context"
+	"path"
+	"time"
+
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// maskedValue replaces secrets in logged messages.
+const maskedValue = "***"
+
+// secretFields are the names of the message fields whose values are never logged.
+var secretFields = map[protoreflect.Name]bool{
+	"auth_token": true,
+	"password":   true,
+}
+
+// logInterceptor logs every RPC through tflog. The method, namespace, table, duration and
+// status code are logged at DEBUG, and the request and response at TRACE with secrets
+// masked. Logs go to the logger of the context, so they are discarded outside Terraform.
+func (c *Client) logInterceptor(ctx context.Context, method string, req, reply any, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+	logCtx := ctx
+	if c.Password != "" {
+		logCtx = tflog.MaskAllFieldValuesStrings(logCtx, c.Password)
+		logCtx = tflog.MaskMessageStrings(logCtx, c.Password)
+	}
+
+	fields := map[string]any{
+		"grpc_service": path.Base(path.Dir(method)),
+		"grpc_method":  path.Base(method),
+	}
+	if r, ok := req.(interface{ GetNamespaceName() string }); ok && r.GetNamespaceName() != "" {
+		fields["namespace"] = r.GetNamespaceName()
+	}
+	if r, ok := req.(interface{ GetTableName() string }); ok && r.GetTableName() != "" {
+		fields["table"] = r.GetTableName()
+	}
+	tflog.Trace(logCtx, "Sending ScalarDB request", fields, map[string]any{"request": maskedJSON(req)})
+
+	start := time.Now()
+	err := invoker(ctx, method, req, reply, cc, opts...)
+	fields["duration_ms"] = time.Since(start).Milliseconds()
+	st := status.Convert(err)
+	fields["grpc_code"] = st.Code().String()
+
+	if err != nil {
+		fields["grpc_message"] = st.Message()
+		tflog.Debug(logCtx, "ScalarDB RPC failed", fields)
+		return err
+	}
+	tflog.Debug(logCtx, "ScalarDB RPC completed", fields)
+	tflog.Trace(logCtx, "Received ScalarDB response", fields, map[string]any{"response": maskedJSON(reply)})
+	return nil
+}
+
+// maskedJSON returns a message as JSON with the values of secret fields masked.
+func maskedJSON(v any) string {
+	msg, ok := v.(proto.Message)
+	if !ok {
+		return ""
+	}
+	msg = proto.Clone(msg)
+	maskSecrets(msg.ProtoReflect())
+	b, err := protojson.Marshal(msg)
+	if err != nil {
+		return ""
+	}
+	return string(b)
+}
+
+// maskSecrets masks the values of secret fields of a message and its nested messages.
+func maskSecrets(m protoreflect.Message) {
+	var secrets []protoreflect.FieldDescriptor
+	m.Range(func(fd protoreflect.FieldDescriptor, v protoreflect.Value) bool {
+		switch {
+		case fd.IsMap():
+			if fd.MapValue().Kind() == protoreflect.MessageKind {
+				v.Map().Range(func(_ protoreflect.MapKey, value protoreflect.Value) bool {
+					maskSecrets(value.Message())
+					return true
+				})
+			}
+		case fd.IsList():
+			if fd.Kind() == protoreflect.MessageKind {
+				for i := 0; i < v.List().Len(); i++ {
+					maskSecrets(v.List().Get(i).Message())
+				}
+			}
+		case fd.Kind() == protoreflect.MessageKind:
+			maskSecrets(v.Message())
+		case fd.Kind() == protoreflect.StringKind && secretFields[fd.Name()]:
+			secrets = append(secrets, fd)
+		}
+		return true
+	})
+	for _, fd := range secrets {
+		m.Set(fd, protoreflect.ValueOfString(maskedValue))
+	}
+}
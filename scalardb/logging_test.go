@@ -0,0 +1,87 @@
+package scalardb
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-log/tflogtest"
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestLogInterceptor(t *testing.T) {
+	var output bytes.Buffer
+	ctx := tflogtest.RootLogger(context.Background(), &output)
+	client := NewClient("localhost", 60051, "alice", "hunter2")
+
+	token := "secret-token"
+	password := "hunter2"
+	requests := []struct {
+		method string
+		req    any
+		err    error
+	}{
+		{
+			method: "/scalardb.cluster.rpc.v1.DistributedTransactionAdmin/CreateTable",
+			req:    &pb.CreateTableRequest{RequestHeader: &pb.RequestHeader{AuthToken: &token}, NamespaceName: "ns", TableName: "users"},
+			err:    status.Error(codes.PermissionDenied, "user alice does not have the CREATE privilege"),
+		},
+		{
+			method: "/scalardb.cluster.rpc.v1.DistributedTransactionAdmin/CreateUser",
+			req:    &pb.CreateUserRequest{RequestHeader: &pb.RequestHeader{AuthToken: &token}, Username: "bob", Password: &password},
+		},
+	}
+	for _, r := range requests {
+		invoker := func(context.Context, string, any, any, *grpc.ClientConn, ...grpc.CallOption) error {
+			return r.err
+		}
+		err := client.logInterceptor(ctx, r.method, r.req, &pb.CreateTableResponse{}, nil, invoker)
+		if err != r.err {
+			t.Errorf("got %v, want %v", err, r.err)
+		}
+	}
+
+	for _, secret := range []string{token, password} {
+		if strings.Contains(output.String(), secret) {
+			t.Errorf("the logs contain secret %q:\n%s", secret, output.String())
+		}
+	}
+
+	if !strings.Contains(output.String(), maskedValue) {
+		t.Errorf("the request was not logged with the auth token masked:\n%s", output.String())
+	}
+
+	entries, err := tflogtest.MultilineJSONDecode(&output)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var failed map[string]any
+	for _, entry := range entries {
+		if entry["@message"] == "ScalarDB RPC failed" {
+			failed = entry
+		}
+	}
+	if failed == nil {
+		t.Fatalf("the failed RPC was not logged: %v", entries)
+	}
+	want := map[string]any{
+		"@level":       "debug",
+		"grpc_service": "scalardb.cluster.rpc.v1.DistributedTransactionAdmin",
+		"grpc_method":  "CreateTable",
+		"namespace":    "ns",
+		"table":        "users",
+		"grpc_code":    "PermissionDenied",
+	}
+	for key, value := range want {
+		if failed[key] != value {
+			t.Errorf("%s: got %v, want %v", key, failed[key], value)
+		}
+	}
+	if _, ok := failed["duration_ms"]; !ok {
+		t.Error("the duration was not logged")
+	}
+}
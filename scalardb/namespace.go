@@ -0,0 +1,130 @@
+package scalardb
+
+import (
+	"context"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+// CreateNamespace creates a new namespace in ScalarDB.
+func (c *Client) CreateNamespace(ctx context.Context, name string, options *NamespaceOptions) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.CreateNamespaceRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: name,
+		Options:       options.Map(),
+		IfNotExists:   true,
+	}
+
+	_, err = admin.CreateNamespace(ctx, req)
+	if err != nil {
+		return newError("create namespace", "CREATE", err)
+	}
+
+	return nil
+}
+
+// DeleteNamespace deletes a namespace from ScalarDB.
+func (c *Client) DeleteNamespace(ctx context.Context, name string) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.DropNamespaceRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: name,
+		IfExists:      true,
+	}
+
+	_, err = admin.DropNamespace(ctx, req)
+	if err != nil {
+		return newError("delete namespace", "DROP", err)
+	}
+
+	return nil
+}
+
+// RepairNamespace repairs the metadata of a namespace in ScalarDB using the given options.
+func (c *Client) RepairNamespace(ctx context.Context, name string, options *NamespaceOptions) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.RepairNamespaceRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: name,
+		Options:       options.Map(),
+	}
+
+	_, err = admin.RepairNamespace(ctx, req)
+	if err != nil {
+		return newError("repair namespace", "CREATE", err)
+	}
+
+	return nil
+}
+
+// NamespaceExists checks if a namespace exists in ScalarDB.
+func (c *Client) NamespaceExists(ctx context.Context, name string) (bool, error) {
+	admin, err := c.adminClient()
+	if err != nil {
+		return false, err
+	}
+
+	req := &pb.NamespaceExistsRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: name,
+	}
+
+	resp, err := admin.NamespaceExists(ctx, req)
+	if err != nil {
+		return false, newError("check if namespace exists", "", err)
+	}
+
+	return resp.Exists, nil
+}
+
+// GetNamespaceNames returns the names of all namespaces.
+func (c *Client) GetNamespaceNames(ctx context.Context) ([]string, error) {
+	admin, err := c.adminClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.GetNamespaceNamesRequest{
+		RequestHeader: c.getRequestHeader(),
+	}
+
+	resp, err := admin.GetNamespaceNames(ctx, req)
+	if err != nil {
+		return nil, newError("get namespace names", "", err)
+	}
+
+	return resp.NamespaceNames, nil
+}
+
+// GetNamespaceTableNames returns the names of the tables in a namespace.
+func (c *Client) GetNamespaceTableNames(ctx context.Context, namespace string) ([]string, error) {
+	admin, err := c.adminClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.GetNamespaceTableNamesRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+	}
+
+	resp, err := admin.GetNamespaceTableNames(ctx, req)
+	if err != nil {
+		return nil, newError("get table names of namespace", "", err)
+	}
+
+	return resp.TableNames, nil
+}
@@ -0,0 +1,147 @@
+package scalardb
+
+import (
+	"context"
+	"fmt"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+// CreateTable creates a new table in ScalarDB.
+func (c *Client) CreateTable(ctx context.Context, namespace, name string, table *TableDefinition) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.CreateTableRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     name,
+		TableMetadata: buildTableMetadata(table),
+		Options:       table.CreationOptions(),
+		IfNotExists:   true,
+	}
+
+	_, err = admin.CreateTable(ctx, req)
+	if err != nil {
+		return newError("create table", "CREATE", err)
+	}
+
+	return nil
+}
+
+// RepairTable repairs the metadata of a table in ScalarDB using the given table definition.
+func (c *Client) RepairTable(ctx context.Context, namespace, name string, table *TableDefinition) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.RepairTableRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     name,
+		TableMetadata: buildTableMetadata(table),
+		Options:       table.CreationOptions(),
+	}
+
+	_, err = admin.RepairTable(ctx, req)
+	if err != nil {
+		return newError("repair table", "CREATE", err)
+	}
+
+	return nil
+}
+
+// AddNewColumnToTable adds a new column to an existing table in ScalarDB.
+func (c *Client) AddNewColumnToTable(ctx context.Context, namespace, name string, column ColumnDefinition) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.AddNewColumnToTableRequest{
+		RequestHeader:  c.getRequestHeader(),
+		NamespaceName:  namespace,
+		TableName:      name,
+		ColumnName:     column.Name,
+		ColumnDataType: convertDataType(column.Type),
+		Encrypted:      column.Encrypted,
+	}
+
+	_, err = admin.AddNewColumnToTable(ctx, req)
+	if err != nil {
+		return newError("add column to table", "ALTER", err)
+	}
+
+	return nil
+}
+
+// DeleteTable deletes a table from ScalarDB.
+func (c *Client) DeleteTable(ctx context.Context, namespace, name string) error {
+	admin, err := c.adminClient()
+	if err != nil {
+		return err
+	}
+
+	req := &pb.DropTableRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     name,
+		IfExists:      true,
+	}
+
+	_, err = admin.DropTable(ctx, req)
+	if err != nil {
+		return newError("delete table", "DROP", err)
+	}
+
+	return nil
+}
+
+// TableExists checks if a table exists in ScalarDB.
+func (c *Client) TableExists(ctx context.Context, namespace, name string) (bool, error) {
+	admin, err := c.adminClient()
+	if err != nil {
+		return false, err
+	}
+
+	req := &pb.TableExistsRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     name,
+	}
+
+	resp, err := admin.TableExists(ctx, req)
+	if err != nil {
+		return false, newError("check if table exists", "", err)
+	}
+
+	return resp.Exists, nil
+}
+
+// GetTableSchema gets the schema of a table from ScalarDB.
+func (c *Client) GetTableSchema(ctx context.Context, namespace, name string) (*TableDefinition, error) {
+	admin, err := c.adminClient()
+	if err != nil {
+		return nil, err
+	}
+
+	req := &pb.GetTableMetadataRequest{
+		RequestHeader: c.getRequestHeader(),
+		NamespaceName: namespace,
+		TableName:     name,
+	}
+
+	resp, err := admin.GetTableMetadata(ctx, req)
+	if err != nil {
+		return nil, newError("get table metadata", "", err)
+	}
+
+	if resp.TableMetadata == nil {
+		return nil, fmt.Errorf("failed to get table metadata: %w", ErrNotFound)
+	}
+
+	return flattenTableMetadata(resp.TableMetadata), nil
+}
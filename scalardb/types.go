@@ -0,0 +1,126 @@
+package scalardb
+
+import "strconv"
+
+// TableDefinition describes the schema of a ScalarDB table.
+// PartitionKey and ClusteringKey are ordered; the order defines the data layout.
+type TableDefinition struct {
+	Columns          []ColumnDefinition
+	PartitionKey     []string
+	ClusteringKey    []string
+	ClusteringOrder  map[string]string
+	SecondaryIndexes []string
+	// Storage holds the typed storage-specific creation options.
+	Storage StorageOptions
+	// Options are additional creation options. They take precedence over Storage.
+	Options map[string]string
+}
+
+// ColumnDefinition describes a column of a ScalarDB table.
+type ColumnDefinition struct {
+	Name      string
+	Type      string
+	Encrypted bool
+}
+
+// NamespaceOptions holds the creation options of a ScalarDB namespace.
+type NamespaceOptions struct {
+	// DurableWrites is omitted if nil, so that ScalarDB applies its own default.
+	DurableWrites *bool
+	// Storage holds the typed storage-specific creation options.
+	Storage StorageOptions
+	// Options are additional creation options. They take precedence over the typed fields.
+	Options map[string]string
+}
+
+// StorageOptions holds the creation options of the storage backends.
+// At most one of the fields is usually set.
+type StorageOptions struct {
+	Cassandra *CassandraOptions
+	Dynamo    *DynamoOptions
+	Cosmos    *CosmosOptions
+}
+
+// CassandraOptions holds the creation options of the Cassandra storage.
+// Zero values are omitted so that ScalarDB applies its own defaults.
+type CassandraOptions struct {
+	// ReplicationStrategy is SimpleStrategy or NetworkTopologyStrategy. Namespaces only.
+	ReplicationStrategy string
+	// ReplicationFactor is the replication factor. Namespaces only.
+	ReplicationFactor int
+	// CompactionStrategy is STCS, LCS or TWCS. Tables only.
+	CompactionStrategy string
+}
+
+// DynamoOptions holds the creation options of the DynamoDB storage.
+// Zero values and nil flags are omitted so that ScalarDB applies its own defaults.
+type DynamoOptions struct {
+	RU        int
+	NoScaling *bool
+	NoBackup  *bool
+}
+
+// CosmosOptions holds the creation options of the Cosmos DB storage.
+type CosmosOptions struct {
+	RU int
+}
+
+// Map returns the namespace options as the string map sent to ScalarDB.
+func (o *NamespaceOptions) Map() map[string]string {
+	if o == nil {
+		return map[string]string{}
+	}
+	options := make(map[string]string)
+	if o.DurableWrites != nil {
+		options["durable_writes"] = strconv.FormatBool(*o.DurableWrites)
+	}
+	for key, value := range o.Storage.Map() {
+		options[key] = value
+	}
+	for key, value := range o.Options {
+		options[key] = value
+	}
+	return options
+}
+
+// CreationOptions returns the creation options of the table as the string map sent to ScalarDB.
+func (t *TableDefinition) CreationOptions() map[string]string {
+	options := t.Storage.Map()
+	for key, value := range t.Options {
+		options[key] = value
+	}
+	return options
+}
+
+// Map returns the storage options as a string map using the option keys of ScalarDB.
+func (s StorageOptions) Map() map[string]string {
+	options := make(map[string]string)
+	if c := s.Cassandra; c != nil {
+		if c.ReplicationStrategy != "" {
+			options["replication-strategy"] = c.ReplicationStrategy
+		}
+		if c.ReplicationFactor > 0 {
+			options["replication-factor"] = strconv.Itoa(c.ReplicationFactor)
+		}
+		if c.CompactionStrategy != "" {
+			options["compaction-strategy"] = c.CompactionStrategy
+		}
+	}
+	if d := s.Dynamo; d != nil {
+		if d.RU > 0 {
+			options["ru"] = strconv.Itoa(d.RU)
+		}
+		if d.NoScaling != nil {
+			options["no-scaling"] = strconv.FormatBool(*d.NoScaling)
+		}
+		if d.NoBackup != nil {
+			options["no-backup"] = strconv.FormatBool(*d.NoBackup)
+		}
+	}
+	if c := s.Cosmos; c != nil {
+		if c.RU > 0 {
+			options["ru"] = strconv.Itoa(c.RU)
+		}
+	}
+	return options
+}
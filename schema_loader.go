@@ -0,0 +1,292 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/scalar-labs/terraform-provider-scalardb/scalardb"
+)
+
+// dataTypes are the column data types supported by ScalarDB.
+var dataTypes = []string{
+	"BOOLEAN", "INT", "BIGINT", "FLOAT", "DOUBLE", "TEXT", "BLOB",
+	"DATE", "TIME", "TIMESTAMP", "TIMESTAMPTZ",
+}
+
+// schemaLoaderTable is a table in the ScalarDB Schema Loader JSON format.
+type schemaLoaderTable struct {
+	Namespace   string
+	Name        string
+	Transaction bool
+	Definition  *scalardb.TableDefinition
+
+	// unknownFields are the fields that are neither table fields nor well-known options.
+	unknownFields []string
+}
+
+// Key returns the "namespace.table" key of the table in the Schema Loader format.
+func (t *schemaLoaderTable) Key() string {
+	return t.Namespace + "." + t.Name
+}
+
+// schemaLoaderFields are the table fields of the Schema Loader format that are not
+// creation options.
+var schemaLoaderFields = []string{"transaction", "partition-key", "clustering-key", "columns", "secondary-index"}
+
+// parseSchemaLoaderJSON parses a ScalarDB Schema Loader JSON document.
+// Fields that are neither table fields nor well-known creation options are rejected,
+// so that a misspelled field is not passed to ScalarDB as an option.
+func parseSchemaLoaderJSON(doc string) (map[string]*schemaLoaderTable, error) {
+	var raw map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(doc), &raw); err != nil {
+		return nil, fmt.Errorf("invalid schema JSON: %w", err)
+	}
+
+	tables := make(map[string]*schemaLoaderTable, len(raw))
+	for key, tableJSON := range raw {
+		table, err := parseSchemaLoaderTable(key, tableJSON)
+		if err != nil {
+			return nil, err
+		}
+		if errs := unknownFieldErrors(table.unknownFields); len(errs) > 0 {
+			return nil, fmt.Errorf("%s: %s", key, strings.Join(errs, "; "))
+		}
+		tables[key] = table
+	}
+
+	return tables, nil
+}
+
+// parseSchemaLoaderTable parses a single "namespace.table" entry of a Schema Loader document.
+func parseSchemaLoaderTable(key string, tableJSON []byte) (*schemaLoaderTable, error) {
+	namespace, name, ok := strings.Cut(key, ".")
+	if !ok || namespace == "" || name == "" {
+		return nil, fmt.Errorf("%s: table keys must have the form namespace.table", key)
+	}
+
+	var fields map[string]interface{}
+	decoder := json.NewDecoder(bytes.NewReader(tableJSON))
+	decoder.UseNumber()
+	if err := decoder.Decode(&fields); err != nil {
+		return nil, fmt.Errorf("%s: %w", key, err)
+	}
+
+	table := &schemaLoaderTable{
+		Namespace:   namespace,
+		Name:        name,
+		Transaction: true,
+		Definition: &scalardb.TableDefinition{
+			ClusteringOrder: make(map[string]string),
+			Options:         make(map[string]string),
+		},
+	}
+	def := table.Definition
+
+	var errs []string
+	for field, value := range fields {
+		switch field {
+		case "transaction":
+			transaction, ok := value.(bool)
+			if !ok {
+				errs = append(errs, "transaction must be a boolean")
+			}
+			table.Transaction = transaction
+		case "partition-key":
+			def.PartitionKey = stringList(value)
+		case "clustering-key":
+			for _, entry := range stringList(value) {
+				colName, order, _ := strings.Cut(strings.TrimSpace(entry), " ")
+				order = strings.ToUpper(strings.TrimSpace(order))
+				if order == "" {
+					order = "ASC"
+				}
+				def.ClusteringKey = append(def.ClusteringKey, colName)
+				def.ClusteringOrder[colName] = order
+			}
+		case "columns":
+			columns, ok := value.(map[string]interface{})
+			if !ok {
+				errs = append(errs, "columns must be an object of column names and data types")
+				continue
+			}
+			for colName, colType := range columns {
+				typeName, _ := colType.(string)
+				def.Columns = append(def.Columns, scalardb.ColumnDefinition{
+					Name: colName,
+					Type: strings.ToUpper(typeName),
+				})
+			}
+		case "secondary-index":
+			def.SecondaryIndexes = stringList(value)
+		default:
+			// Any other field is a creation option, e.g. compaction-strategy or ru
+			if _, ok := knownOptions[field]; !ok {
+				table.unknownFields = append(table.unknownFields, field)
+				if !isScalar(value) {
+					continue
+				}
+			}
+			switch v := value.(type) {
+			case string:
+				def.Options[field] = v
+			case json.Number:
+				def.Options[field] = v.String()
+			case bool:
+				def.Options[field] = fmt.Sprintf("%t", v)
+			default:
+				errs = append(errs, fmt.Sprintf("option %s must be a string, number or boolean", field))
+			}
+		}
+	}
+
+	sort.Slice(def.Columns, func(i, j int) bool {
+		return def.Columns[i].Name < def.Columns[j].Name
+	})
+	sort.Strings(def.SecondaryIndexes)
+
+	errs = append(errs, validateSchemaLoaderTable(table)...)
+	if len(errs) > 0 {
+		return nil, fmt.Errorf("%s: %s", key, strings.Join(errs, "; "))
+	}
+
+	return table, nil
+}
+
+// unknownFieldErrors describes unknown table fields, suggesting the field that was
+// likely meant.
+func unknownFieldErrors(fields []string) []string {
+	sort.Strings(fields)
+	errs := make([]string, 0, len(fields))
+	for _, field := range fields {
+		msg := fmt.Sprintf("unknown field %q", field)
+		suggestion := strings.ReplaceAll(strings.ToLower(field), "_", "-")
+		if _, ok := knownOptions[suggestion]; ok || slices.Contains(schemaLoaderFields, suggestion) {
+			msg += fmt.Sprintf(", did you mean %q?", suggestion)
+		}
+		errs = append(errs, msg)
+	}
+	return errs
+}
+
+// isScalar reports whether a decoded JSON value is a string, number or boolean.
+func isScalar(value interface{}) bool {
+	switch value.(type) {
+	case string, json.Number, bool:
+		return true
+	}
+	return false
+}
+
+// validateSchemaLoaderTable checks the key, index and type definitions of a table.
+func validateSchemaLoaderTable(table *schemaLoaderTable) []string {
+	var errs []string
+	def := table.Definition
+
+	if !table.Transaction {
+		errs = append(errs, "non-transactional tables cannot be created through ScalarDB Cluster")
+	}
+	if len(def.PartitionKey) == 0 {
+		errs = append(errs, "partition-key must list at least one column")
+	}
+
+	columns := make(map[string]bool)
+	for _, column := range def.Columns {
+		columns[column.Name] = true
+		if !slices.Contains(dataTypes, column.Type) {
+			errs = append(errs, fmt.Sprintf("column %s has unsupported data type %q", column.Name, column.Type))
+		}
+	}
+	for _, colName := range def.PartitionKey {
+		if !columns[colName] {
+			errs = append(errs, fmt.Sprintf("partition-key column %s is not defined in columns", colName))
+		}
+	}
+	for _, colName := range def.ClusteringKey {
+		if !columns[colName] {
+			errs = append(errs, fmt.Sprintf("clustering-key column %s is not defined in columns", colName))
+		}
+		if slices.Contains(def.PartitionKey, colName) {
+			errs = append(errs, fmt.Sprintf("column %s is in both partition-key and clustering-key", colName))
+		}
+		if order := def.ClusteringOrder[colName]; order != "ASC" && order != "DESC" {
+			errs = append(errs, fmt.Sprintf("clustering-key column %s has invalid order %q", colName, order))
+		}
+	}
+	for _, colName := range def.SecondaryIndexes {
+		if !columns[colName] {
+			errs = append(errs, fmt.Sprintf("secondary-index column %s is not defined in columns", colName))
+		}
+	}
+
+	return errs
+}
+
+// renderSchemaLoaderTable renders a table as a canonical Schema Loader JSON object.
+// Equal tables always render to the same string.
+func renderSchemaLoaderTable(table *schemaLoaderTable) (string, error) {
+	b, err := json.Marshal(schemaLoaderTableFields(table))
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// renderSchemaLoaderJSON renders tables as an indented Schema Loader JSON document.
+func renderSchemaLoaderJSON(tables []*schemaLoaderTable) (string, error) {
+	doc := make(map[string]interface{}, len(tables))
+	for _, table := range tables {
+		doc[table.Key()] = schemaLoaderTableFields(table)
+	}
+	b, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// schemaLoaderTableFields returns the fields of a table in the Schema Loader format.
+func schemaLoaderTableFields(table *schemaLoaderTable) map[string]interface{} {
+	def := table.Definition
+
+	fields := make(map[string]interface{})
+	for key, value := range def.Options {
+		fields[key] = value
+	}
+
+	columns := make(map[string]string, len(def.Columns))
+	for _, column := range def.Columns {
+		columns[column.Name] = column.Type
+	}
+
+	clusteringKey := make([]string, 0, len(def.ClusteringKey))
+	for _, colName := range def.ClusteringKey {
+		clusteringKey = append(clusteringKey, colName+" "+clusteringOrderOrDefault(def.ClusteringOrder, colName))
+	}
+
+	secondaryIndex := append([]string{}, def.SecondaryIndexes...)
+	sort.Strings(secondaryIndex)
+
+	fields["transaction"] = table.Transaction
+	fields["partition-key"] = append([]string{}, def.PartitionKey...)
+	fields["clustering-key"] = clusteringKey
+	fields["columns"] = columns
+	fields["secondary-index"] = secondaryIndex
+
+	return fields
+}
+
+// stringList converts a decoded JSON array to a string slice, ignoring non-string elements.
+func stringList(value interface{}) []string {
+	items, _ := value.([]interface{})
+	list := make([]string, 0, len(items))
+	for _, item := range items {
+		if s, ok := item.(string); ok {
+			list = append(list, s)
+		}
+	}
+	return list
+}
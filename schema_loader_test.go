@@ -0,0 +1,117 @@
+package main
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseSchemaLoaderJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		doc     string
+		wantErr string
+	}{
+		{name: "not JSON", doc: `{"app.orders": `, wantErr: "invalid schema JSON"},
+		{name: "not an object", doc: `["app.orders"]`, wantErr: "invalid schema JSON"},
+		{name: "key without namespace", doc: `{"orders": {}}`, wantErr: "namespace.table"},
+		{name: "table not an object", doc: `{"app.orders": "orders"}`, wantErr: "app.orders"},
+		{name: "columns not an object", doc: `{"app.orders": {"partition-key": ["id"], "columns": ["id"]}}`, wantErr: "columns must be an object"},
+		{name: "transaction not a boolean", doc: `{"app.orders": {"transaction": "yes", "partition-key": ["id"], "columns": {"id": "INT"}}}`, wantErr: "transaction must be a boolean"},
+		{name: "non-transactional", doc: `{"app.orders": {"transaction": false, "partition-key": ["id"], "columns": {"id": "INT"}}}`, wantErr: "non-transactional"},
+		{name: "unknown data type", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"id": "UUID"}}}`, wantErr: "unsupported data type"},
+		{name: "undefined key column", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"item": "TEXT"}}}`, wantErr: "partition-key column id"},
+		{name: "invalid clustering order", doc: `{"app.orders": {"partition-key": ["id"], "clustering-key": ["ts UP"], "columns": {"id": "INT", "ts": "BIGINT"}}}`, wantErr: "invalid order"},
+		{name: "nested option", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"id": "INT"}, "ru": {"read": 10}}}`, wantErr: "option ru"},
+		{name: "misspelled field", doc: `{"app.orders": {"partition_key": ["id"], "partition-key": ["id"], "columns": {"id": "INT"}}}`, wantErr: `unknown field "partition_key", did you mean "partition-key"?`},
+		{name: "misspelled option", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"id": "INT"}, "Compaction_Strategy": "LCS"}}`, wantErr: `did you mean "compaction-strategy"?`},
+		{name: "unknown option", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"id": "INT"}, "ttl": 3600}}`, wantErr: `unknown field "ttl"`},
+		{name: "valid", doc: `{"app.orders": {"partition-key": ["id"], "columns": {"id": "int"}, "ru": 10, "no-backup": true}}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tables, err := parseSchemaLoaderJSON(tt.doc)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("got error %v, want one containing %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			def := tables["app.orders"].Definition
+			if def.Columns[0].Type != "INT" {
+				t.Errorf("data type: got %q, want INT", def.Columns[0].Type)
+			}
+			if def.Options["ru"] != "10" || def.Options["no-backup"] != "true" {
+				t.Errorf("options: got %v", def.Options)
+			}
+		})
+	}
+}
+
+func TestParseSchemaLoaderJSONKeyOrder(t *testing.T) {
+	// The same table with its fields and columns in a different order
+	docs := []string{
+		`{"app.events": {
+			"partition-key": ["tenant", "id"],
+			"clustering-key": ["ts DESC", "seq"],
+			"columns": {"tenant": "TEXT", "id": "INT", "ts": "BIGINT", "seq": "INT", "body": "TEXT"},
+			"secondary-index": ["seq", "body"]
+		}}`,
+		`{"app.events": {
+			"secondary-index": ["body", "seq"],
+			"columns": {"body": "TEXT", "seq": "INT", "ts": "BIGINT", "id": "INT", "tenant": "TEXT"},
+			"clustering-key": ["ts desc", "seq ASC"],
+			"partition-key": ["tenant", "id"]
+		}}`,
+	}
+
+	var rendered []string
+	for _, doc := range docs {
+		tables, err := parseSchemaLoaderJSON(doc)
+		if err != nil {
+			t.Fatal(err)
+		}
+		def := tables["app.events"].Definition
+		// Key columns keep their declared order, since it defines the data layout
+		if want := []string{"tenant", "id"}; !slices.Equal(def.PartitionKey, want) {
+			t.Errorf("partition key: got %v, want %v", def.PartitionKey, want)
+		}
+		if want := []string{"ts", "seq"}; !slices.Equal(def.ClusteringKey, want) {
+			t.Errorf("clustering key: got %v, want %v", def.ClusteringKey, want)
+		}
+		if def.ClusteringOrder["ts"] != "DESC" || def.ClusteringOrder["seq"] != "ASC" {
+			t.Errorf("clustering order: got %v", def.ClusteringOrder)
+		}
+		out, err := renderSchemaLoaderTable(tables["app.events"])
+		if err != nil {
+			t.Fatal(err)
+		}
+		rendered = append(rendered, out)
+	}
+	if rendered[0] != rendered[1] {
+		t.Errorf("the documents render differently:\n%s\n%s", rendered[0], rendered[1])
+	}
+
+	// Swapping key columns is a different table
+	tables, err := parseSchemaLoaderJSON(`{"app.events": {"partition-key": ["id", "tenant"], "columns": {"tenant": "TEXT", "id": "INT"}}}`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"id", "tenant"}; !slices.Equal(tables["app.events"].Definition.PartitionKey, want) {
+		t.Errorf("partition key: got %v, want %v", tables["app.events"].Definition.PartitionKey, want)
+	}
+}
+
+func TestParseSchemaLoaderTableUnknownFields(t *testing.T) {
+	// Tables saved in the state before unknown fields were rejected must still be read
+	table, err := parseSchemaLoaderTable("app.orders", []byte(`{"partition-key": ["id"], "columns": {"id": "INT"}, "ttl": "3600"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if table.Definition.Options["ttl"] != "3600" {
+		t.Errorf("options: got %v", table.Definition.Options)
+	}
+}
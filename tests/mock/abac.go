@@ -0,0 +1,813 @@
+package mock
+
+import (
+	"context"
+	"slices"
+	"sort"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultDataTagColumnName is the data tag column of a policy created without one.
+const defaultDataTagColumnName = "data_tag"
+
+// abacPolicy is an ABAC policy together with the levels, compartments and groups defined
+// in it and the tags assigned to users under it.
+type abacPolicy struct {
+	policy       *pb.Policy
+	levels       map[string]*pb.Level
+	compartments map[string]*pb.Compartment
+	groups       map[string]*pb.Group
+	userTags     map[string]*pb.UserTagInfo
+}
+
+// lookupPolicy returns a policy, or NotFound if it does not exist. The caller must hold mu.
+func (s *Server) lookupPolicy(name string) (*abacPolicy, error) {
+	policy, ok := s.policies[name]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "policy %s does not exist", name)
+	}
+	return policy, nil
+}
+
+// lookupLevel returns a level of the policy, or NotFound if it does not exist.
+func (p *abacPolicy) lookupLevel(shortName string) (*pb.Level, error) {
+	level, ok := p.levels[shortName]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "level %s does not exist in policy %s", shortName, p.policy.Name)
+	}
+	return level, nil
+}
+
+// userTag returns the tags of a user under the policy, creating them if needed.
+// It returns NotFound if the user does not exist. The caller must hold mu.
+func (s *Server) userTag(p *abacPolicy, username string) (*pb.UserTagInfo, error) {
+	if _, err := s.lookupUser(username); err != nil {
+		return nil, err
+	}
+	info, ok := p.userTags[username]
+	if !ok {
+		info = &pb.UserTagInfo{
+			PolicyName:      p.policy.Name,
+			Username:        username,
+			LevelInfo:       &pb.UserTagInfo_LevelInfo{},
+			CompartmentInfo: &pb.UserTagInfo_CompartmentInfo{},
+			GroupInfo:       &pb.UserTagInfo_GroupInfo{},
+		}
+		p.userTags[username] = info
+	}
+	return info, nil
+}
+
+// setPolicyState changes the state of a policy.
+func (s *Server) setPolicyState(name string, state pb.PolicyState) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(name)
+	if err != nil {
+		return err
+	}
+	policy.policy.State = state
+	return nil
+}
+
+// CreatePolicy implements the CreatePolicy RPC.
+func (s *Server) CreatePolicy(ctx context.Context, req *pb.CreatePolicyRequest) (*pb.CreatePolicyResponse, error) {
+	s.logf("CreatePolicy: %v", req)
+	if err := requireName("policy", req.PolicyName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, exists := s.policies[req.PolicyName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "policy %s already exists", req.PolicyName)
+	}
+	dataTagColumnName := defaultDataTagColumnName
+	if req.DataTagColumnName != nil {
+		dataTagColumnName = *req.DataTagColumnName
+	}
+	s.policies[req.PolicyName] = &abacPolicy{
+		policy: &pb.Policy{
+			Name:              req.PolicyName,
+			DataTagColumnName: dataTagColumnName,
+			State:             pb.PolicyState_POLICY_STATE_ENABLED,
+		},
+		levels:       make(map[string]*pb.Level),
+		compartments: make(map[string]*pb.Compartment),
+		groups:       make(map[string]*pb.Group),
+		userTags:     make(map[string]*pb.UserTagInfo),
+	}
+	return &pb.CreatePolicyResponse{}, nil
+}
+
+// EnablePolicy implements the EnablePolicy RPC.
+func (s *Server) EnablePolicy(ctx context.Context, req *pb.EnablePolicyRequest) (*pb.EnablePolicyResponse, error) {
+	s.logf("EnablePolicy: %v", req)
+	if err := s.setPolicyState(req.PolicyName, pb.PolicyState_POLICY_STATE_ENABLED); err != nil {
+		return nil, err
+	}
+	return &pb.EnablePolicyResponse{}, nil
+}
+
+// DisablePolicy implements the DisablePolicy RPC.
+func (s *Server) DisablePolicy(ctx context.Context, req *pb.DisablePolicyRequest) (*pb.DisablePolicyResponse, error) {
+	s.logf("DisablePolicy: %v", req)
+	if err := s.setPolicyState(req.PolicyName, pb.PolicyState_POLICY_STATE_DISABLED); err != nil {
+		return nil, err
+	}
+	return &pb.DisablePolicyResponse{}, nil
+}
+
+// GetPolicy implements the GetPolicy RPC. The policy is left unset if it does not exist.
+func (s *Server) GetPolicy(ctx context.Context, req *pb.GetPolicyRequest) (*pb.GetPolicyResponse, error) {
+	s.logf("GetPolicy: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, ok := s.policies[req.PolicyName]
+	if !ok {
+		return &pb.GetPolicyResponse{}, nil
+	}
+	return &pb.GetPolicyResponse{Policy: clone(policy.policy)}, nil
+}
+
+// GetPolicies implements the GetPolicies RPC.
+func (s *Server) GetPolicies(ctx context.Context, req *pb.GetPoliciesRequest) (*pb.GetPoliciesResponse, error) {
+	s.logf("GetPolicies: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.GetPoliciesResponse{}
+	for _, name := range sortedKeys(s.policies) {
+		resp.Policies = append(resp.Policies, clone(s.policies[name].policy))
+	}
+	return resp, nil
+}
+
+// CreateLevel implements the CreateLevel RPC.
+func (s *Server) CreateLevel(ctx context.Context, req *pb.CreateLevelRequest) (*pb.CreateLevelResponse, error) {
+	s.logf("CreateLevel: %v", req)
+	if err := requireName("level", req.LevelShortName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.levels[req.LevelShortName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "level %s already exists in policy %s", req.LevelShortName, req.PolicyName)
+	}
+	for _, level := range policy.levels {
+		if level.LevelNumber == req.LevelNumber {
+			return nil, status.Errorf(codes.InvalidArgument, "level number %d is already used by level %s", req.LevelNumber, level.ShortName)
+		}
+	}
+	policy.levels[req.LevelShortName] = &pb.Level{
+		PolicyName:  req.PolicyName,
+		ShortName:   req.LevelShortName,
+		LongName:    req.LevelLongName,
+		LevelNumber: req.LevelNumber,
+	}
+	return &pb.CreateLevelResponse{}, nil
+}
+
+// DropLevel implements the DropLevel RPC.
+func (s *Server) DropLevel(ctx context.Context, req *pb.DropLevelRequest) (*pb.DropLevelResponse, error) {
+	s.logf("DropLevel: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := policy.lookupLevel(req.LevelShortName); err != nil {
+		return nil, err
+	}
+	for username, info := range policy.userTags {
+		levels := info.LevelInfo
+		if slices.Contains([]string{levels.LevelShortName, levels.DefaultLevelShortName, levels.RowLevelShortName}, req.LevelShortName) {
+			return nil, status.Errorf(codes.InvalidArgument, "level %s is assigned to user %s", req.LevelShortName, username)
+		}
+	}
+	delete(policy.levels, req.LevelShortName)
+	return &pb.DropLevelResponse{}, nil
+}
+
+// GetLevel implements the GetLevel RPC. The level is left unset if it does not exist.
+func (s *Server) GetLevel(ctx context.Context, req *pb.GetLevelRequest) (*pb.GetLevelResponse, error) {
+	s.logf("GetLevel: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	level, ok := policy.levels[req.LevelShortName]
+	if !ok {
+		return &pb.GetLevelResponse{}, nil
+	}
+	return &pb.GetLevelResponse{Level: clone(level)}, nil
+}
+
+// GetLevels implements the GetLevels RPC. Levels are returned in order of their numbers.
+func (s *Server) GetLevels(ctx context.Context, req *pb.GetLevelsRequest) (*pb.GetLevelsResponse, error) {
+	s.logf("GetLevels: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetLevelsResponse{}
+	for _, level := range policy.levels {
+		resp.Levels = append(resp.Levels, clone(level))
+	}
+	sort.Slice(resp.Levels, func(i, j int) bool {
+		return resp.Levels[i].LevelNumber < resp.Levels[j].LevelNumber
+	})
+	return resp, nil
+}
+
+// CreateCompartment implements the CreateCompartment RPC.
+func (s *Server) CreateCompartment(ctx context.Context, req *pb.CreateCompartmentRequest) (*pb.CreateCompartmentResponse, error) {
+	s.logf("CreateCompartment: %v", req)
+	if err := requireName("compartment", req.CompartmentShortName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.compartments[req.CompartmentShortName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "compartment %s already exists in policy %s", req.CompartmentShortName, req.PolicyName)
+	}
+	policy.compartments[req.CompartmentShortName] = &pb.Compartment{
+		PolicyName: req.PolicyName,
+		ShortName:  req.CompartmentShortName,
+		LongName:   req.CompartmentLongName,
+	}
+	return &pb.CreateCompartmentResponse{}, nil
+}
+
+// DropCompartment implements the DropCompartment RPC.
+func (s *Server) DropCompartment(ctx context.Context, req *pb.DropCompartmentRequest) (*pb.DropCompartmentResponse, error) {
+	s.logf("DropCompartment: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.compartments[req.CompartmentShortName]; !exists {
+		return nil, status.Errorf(codes.NotFound, "compartment %s does not exist in policy %s", req.CompartmentShortName, req.PolicyName)
+	}
+	for username, info := range policy.userTags {
+		if slices.Contains(info.CompartmentInfo.ReadCompartmentShortNames, req.CompartmentShortName) {
+			return nil, status.Errorf(codes.InvalidArgument, "compartment %s is assigned to user %s", req.CompartmentShortName, username)
+		}
+	}
+	delete(policy.compartments, req.CompartmentShortName)
+	return &pb.DropCompartmentResponse{}, nil
+}
+
+// GetCompartment implements the GetCompartment RPC. The compartment is left unset if it
+// does not exist.
+func (s *Server) GetCompartment(ctx context.Context, req *pb.GetCompartmentRequest) (*pb.GetCompartmentResponse, error) {
+	s.logf("GetCompartment: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	compartment, ok := policy.compartments[req.CompartmentShortName]
+	if !ok {
+		return &pb.GetCompartmentResponse{}, nil
+	}
+	return &pb.GetCompartmentResponse{Compartment: clone(compartment)}, nil
+}
+
+// GetCompartments implements the GetCompartments RPC.
+func (s *Server) GetCompartments(ctx context.Context, req *pb.GetCompartmentsRequest) (*pb.GetCompartmentsResponse, error) {
+	s.logf("GetCompartments: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetCompartmentsResponse{}
+	for _, name := range sortedKeys(policy.compartments) {
+		resp.Compartments = append(resp.Compartments, clone(policy.compartments[name]))
+	}
+	return resp, nil
+}
+
+// CreateGroup implements the CreateGroup RPC.
+func (s *Server) CreateGroup(ctx context.Context, req *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
+	s.logf("CreateGroup: %v", req)
+	if err := requireName("group", req.GroupShortName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.groups[req.GroupShortName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "group %s already exists in policy %s", req.GroupShortName, req.PolicyName)
+	}
+	if req.ParentGroupShortName != nil {
+		if _, exists := policy.groups[*req.ParentGroupShortName]; !exists {
+			return nil, status.Errorf(codes.NotFound, "parent group %s does not exist in policy %s", *req.ParentGroupShortName, req.PolicyName)
+		}
+	}
+	policy.groups[req.GroupShortName] = &pb.Group{
+		PolicyName:           req.PolicyName,
+		ShortName:            req.GroupShortName,
+		LongName:             req.GroupLongName,
+		ParentGroupShortName: req.ParentGroupShortName,
+	}
+	return &pb.CreateGroupResponse{}, nil
+}
+
+// DropGroup implements the DropGroup RPC.
+func (s *Server) DropGroup(ctx context.Context, req *pb.DropGroupRequest) (*pb.DropGroupResponse, error) {
+	s.logf("DropGroup: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.groups[req.GroupShortName]; !exists {
+		return nil, status.Errorf(codes.NotFound, "group %s does not exist in policy %s", req.GroupShortName, req.PolicyName)
+	}
+	for _, group := range policy.groups {
+		if group.GetParentGroupShortName() == req.GroupShortName {
+			return nil, status.Errorf(codes.InvalidArgument, "group %s is the parent of group %s", req.GroupShortName, group.ShortName)
+		}
+	}
+	for username, info := range policy.userTags {
+		if slices.Contains(info.GroupInfo.ReadGroupShortNames, req.GroupShortName) {
+			return nil, status.Errorf(codes.InvalidArgument, "group %s is assigned to user %s", req.GroupShortName, username)
+		}
+	}
+	delete(policy.groups, req.GroupShortName)
+	return &pb.DropGroupResponse{}, nil
+}
+
+// GetGroup implements the GetGroup RPC. The group is left unset if it does not exist.
+func (s *Server) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*pb.GetGroupResponse, error) {
+	s.logf("GetGroup: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	group, ok := policy.groups[req.GroupShortName]
+	if !ok {
+		return &pb.GetGroupResponse{}, nil
+	}
+	return &pb.GetGroupResponse{Group: clone(group)}, nil
+}
+
+// GetGroups implements the GetGroups RPC.
+func (s *Server) GetGroups(ctx context.Context, req *pb.GetGroupsRequest) (*pb.GetGroupsResponse, error) {
+	s.logf("GetGroups: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetGroupsResponse{}
+	for _, name := range sortedKeys(policy.groups) {
+		resp.Groups = append(resp.Groups, clone(policy.groups[name]))
+	}
+	return resp, nil
+}
+
+// SetLevelsToUser implements the SetLevelsToUser RPC. The default level defaults to the
+// level, and the row level defaults to the default level. Neither may be higher than the
+// level before it.
+func (s *Server) SetLevelsToUser(ctx context.Context, req *pb.SetLevelsToUserRequest) (*pb.SetLevelsToUserResponse, error) {
+	s.logf("SetLevelsToUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	level, err := policy.lookupLevel(req.LevelShortName)
+	if err != nil {
+		return nil, err
+	}
+	defaultLevel := level
+	if req.DefaultLevelShortName != nil {
+		if defaultLevel, err = policy.lookupLevel(*req.DefaultLevelShortName); err != nil {
+			return nil, err
+		}
+	}
+	rowLevel := defaultLevel
+	if req.RowLevelShortName != nil {
+		if rowLevel, err = policy.lookupLevel(*req.RowLevelShortName); err != nil {
+			return nil, err
+		}
+	}
+	if defaultLevel.LevelNumber > level.LevelNumber {
+		return nil, status.Errorf(codes.InvalidArgument, "default level %s is higher than level %s", defaultLevel.ShortName, level.ShortName)
+	}
+	if rowLevel.LevelNumber > defaultLevel.LevelNumber {
+		return nil, status.Errorf(codes.InvalidArgument, "row level %s is higher than default level %s", rowLevel.ShortName, defaultLevel.ShortName)
+	}
+
+	info, err := s.userTag(policy, req.Username)
+	if err != nil {
+		return nil, err
+	}
+	info.LevelInfo = &pb.UserTagInfo_LevelInfo{
+		LevelShortName:        level.ShortName,
+		DefaultLevelShortName: defaultLevel.ShortName,
+		RowLevelShortName:     rowLevel.ShortName,
+	}
+	return &pb.SetLevelsToUserResponse{}, nil
+}
+
+// AddCompartmentToUser implements the AddCompartmentToUser RPC.
+func (s *Server) AddCompartmentToUser(ctx context.Context, req *pb.AddCompartmentToUserRequest) (*pb.AddCompartmentToUserResponse, error) {
+	s.logf("AddCompartmentToUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.compartments[req.CompartmentShortName]; !exists {
+		return nil, status.Errorf(codes.NotFound, "compartment %s does not exist in policy %s", req.CompartmentShortName, req.PolicyName)
+	}
+	write, err := writeAccess(req.AccessMode)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.userTag(policy, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	c := info.CompartmentInfo
+	name := req.CompartmentShortName
+	c.ReadCompartmentShortNames = addName(c.ReadCompartmentShortNames, name)
+	if write {
+		c.WriteCompartmentShortNames = addName(c.WriteCompartmentShortNames, name)
+	}
+	if req.DefaultCompartment {
+		c.DefaultReadCompartmentShortNames = addName(c.DefaultReadCompartmentShortNames, name)
+		if write {
+			c.DefaultWriteCompartmentShortNames = addName(c.DefaultWriteCompartmentShortNames, name)
+		}
+	}
+	if req.RowCompartment {
+		c.RowCompartmentShortNames = addName(c.RowCompartmentShortNames, name)
+	}
+	return &pb.AddCompartmentToUserResponse{}, nil
+}
+
+// RemoveCompartmentFromUser implements the RemoveCompartmentFromUser RPC.
+func (s *Server) RemoveCompartmentFromUser(ctx context.Context, req *pb.RemoveCompartmentFromUserRequest) (*pb.RemoveCompartmentFromUserResponse, error) {
+	s.logf("RemoveCompartmentFromUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := policy.userTags[req.Username]
+	if !ok || !slices.Contains(info.CompartmentInfo.ReadCompartmentShortNames, req.CompartmentShortName) {
+		return nil, status.Errorf(codes.NotFound, "compartment %s is not assigned to user %s", req.CompartmentShortName, req.Username)
+	}
+
+	c := info.CompartmentInfo
+	name := req.CompartmentShortName
+	c.ReadCompartmentShortNames = removeName(c.ReadCompartmentShortNames, name)
+	c.WriteCompartmentShortNames = removeName(c.WriteCompartmentShortNames, name)
+	c.DefaultReadCompartmentShortNames = removeName(c.DefaultReadCompartmentShortNames, name)
+	c.DefaultWriteCompartmentShortNames = removeName(c.DefaultWriteCompartmentShortNames, name)
+	c.RowCompartmentShortNames = removeName(c.RowCompartmentShortNames, name)
+	return &pb.RemoveCompartmentFromUserResponse{}, nil
+}
+
+// AddGroupToUser implements the AddGroupToUser RPC.
+func (s *Server) AddGroupToUser(ctx context.Context, req *pb.AddGroupToUserRequest) (*pb.AddGroupToUserResponse, error) {
+	s.logf("AddGroupToUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := policy.groups[req.GroupShortName]; !exists {
+		return nil, status.Errorf(codes.NotFound, "group %s does not exist in policy %s", req.GroupShortName, req.PolicyName)
+	}
+	write, err := writeAccess(req.AccessMode)
+	if err != nil {
+		return nil, err
+	}
+	info, err := s.userTag(policy, req.Username)
+	if err != nil {
+		return nil, err
+	}
+
+	g := info.GroupInfo
+	name := req.GroupShortName
+	g.ReadGroupShortNames = addName(g.ReadGroupShortNames, name)
+	if write {
+		g.WriteGroupShortNames = addName(g.WriteGroupShortNames, name)
+	}
+	if req.DefaultGroup {
+		g.DefaultReadGroupShortNames = addName(g.DefaultReadGroupShortNames, name)
+		if write {
+			g.DefaultWriteGroupShortNames = addName(g.DefaultWriteGroupShortNames, name)
+		}
+	}
+	if req.RowGroup {
+		g.RowGroupShortNames = addName(g.RowGroupShortNames, name)
+	}
+	return &pb.AddGroupToUserResponse{}, nil
+}
+
+// RemoveGroupFromUser implements the RemoveGroupFromUser RPC.
+func (s *Server) RemoveGroupFromUser(ctx context.Context, req *pb.RemoveGroupFromUserRequest) (*pb.RemoveGroupFromUserResponse, error) {
+	s.logf("RemoveGroupFromUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := policy.userTags[req.Username]
+	if !ok || !slices.Contains(info.GroupInfo.ReadGroupShortNames, req.GroupShortName) {
+		return nil, status.Errorf(codes.NotFound, "group %s is not assigned to user %s", req.GroupShortName, req.Username)
+	}
+
+	g := info.GroupInfo
+	name := req.GroupShortName
+	g.ReadGroupShortNames = removeName(g.ReadGroupShortNames, name)
+	g.WriteGroupShortNames = removeName(g.WriteGroupShortNames, name)
+	g.DefaultReadGroupShortNames = removeName(g.DefaultReadGroupShortNames, name)
+	g.DefaultWriteGroupShortNames = removeName(g.DefaultWriteGroupShortNames, name)
+	g.RowGroupShortNames = removeName(g.RowGroupShortNames, name)
+	return &pb.RemoveGroupFromUserResponse{}, nil
+}
+
+// DropUserTagInfoFromUser implements the DropUserTagInfoFromUser RPC.
+func (s *Server) DropUserTagInfoFromUser(ctx context.Context, req *pb.DropUserTagInfoFromUserRequest) (*pb.DropUserTagInfoFromUserResponse, error) {
+	s.logf("DropUserTagInfoFromUser: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.lookupUser(req.Username); err != nil {
+		return nil, err
+	}
+	delete(policy.userTags, req.Username)
+	return &pb.DropUserTagInfoFromUserResponse{}, nil
+}
+
+// GetUserTagInfo implements the GetUserTagInfo RPC. The tag info is left unset if the
+// user has no tags under the policy.
+func (s *Server) GetUserTagInfo(ctx context.Context, req *pb.GetUserTagInfoRequest) (*pb.GetUserTagInfoResponse, error) {
+	s.logf("GetUserTagInfo: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, err := s.lookupPolicy(req.PolicyName)
+	if err != nil {
+		return nil, err
+	}
+	info, ok := policy.userTags[req.Username]
+	if !ok {
+		return &pb.GetUserTagInfoResponse{}, nil
+	}
+	return &pb.GetUserTagInfoResponse{UserTagInfo: clone(info)}, nil
+}
+
+// writeAccess reports whether an access mode grants write access, or returns
+// InvalidArgument if it is not specified.
+func writeAccess(mode pb.AccessMode) (bool, error) {
+	switch mode {
+	case pb.AccessMode_ACCESS_MODE_READ_ONLY:
+		return false, nil
+	case pb.AccessMode_ACCESS_MODE_READ_WRITE:
+		return true, nil
+	default:
+		return false, status.Error(codes.InvalidArgument, "access mode is not specified")
+	}
+}
+
+// CreateNamespacePolicy implements the CreateNamespacePolicy RPC.
+func (s *Server) CreateNamespacePolicy(ctx context.Context, req *pb.CreateNamespacePolicyRequest) (*pb.CreateNamespacePolicyResponse, error) {
+	s.logf("CreateNamespacePolicy: %v", req)
+	if err := requireName("namespace policy", req.NamespacePolicyName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, exists := s.namespacePolicies[req.NamespacePolicyName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "namespace policy %s already exists", req.NamespacePolicyName)
+	}
+	if _, err := s.lookupPolicy(req.PolicyName); err != nil {
+		return nil, err
+	}
+	if err := s.namespaceNotFound(req.NamespaceName); err != nil {
+		return nil, err
+	}
+	s.namespacePolicies[req.NamespacePolicyName] = &pb.NamespacePolicy{
+		Name:          req.NamespacePolicyName,
+		PolicyName:    req.PolicyName,
+		NamespaceName: req.NamespaceName,
+		State:         pb.PolicyState_POLICY_STATE_ENABLED,
+	}
+	return &pb.CreateNamespacePolicyResponse{}, nil
+}
+
+// setNamespacePolicyState changes the state of a namespace policy.
+func (s *Server) setNamespacePolicyState(name string, state pb.PolicyState) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, ok := s.namespacePolicies[name]
+	if !ok {
+		return status.Errorf(codes.NotFound, "namespace policy %s does not exist", name)
+	}
+	policy.State = state
+	return nil
+}
+
+// EnableNamespacePolicy implements the EnableNamespacePolicy RPC.
+func (s *Server) EnableNamespacePolicy(ctx context.Context, req *pb.EnableNamespacePolicyRequest) (*pb.EnableNamespacePolicyResponse, error) {
+	s.logf("EnableNamespacePolicy: %v", req)
+	if err := s.setNamespacePolicyState(req.NamespacePolicyName, pb.PolicyState_POLICY_STATE_ENABLED); err != nil {
+		return nil, err
+	}
+	return &pb.EnableNamespacePolicyResponse{}, nil
+}
+
+// DisableNamespacePolicy implements the DisableNamespacePolicy RPC.
+func (s *Server) DisableNamespacePolicy(ctx context.Context, req *pb.DisableNamespacePolicyRequest) (*pb.DisableNamespacePolicyResponse, error) {
+	s.logf("DisableNamespacePolicy: %v", req)
+	if err := s.setNamespacePolicyState(req.NamespacePolicyName, pb.PolicyState_POLICY_STATE_DISABLED); err != nil {
+		return nil, err
+	}
+	return &pb.DisableNamespacePolicyResponse{}, nil
+}
+
+// GetNamespacePolicy implements the GetNamespacePolicy RPC. The namespace policy is left
+// unset if it does not exist.
+func (s *Server) GetNamespacePolicy(ctx context.Context, req *pb.GetNamespacePolicyRequest) (*pb.GetNamespacePolicyResponse, error) {
+	s.logf("GetNamespacePolicy: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, ok := s.namespacePolicies[req.NamespacePolicyName]
+	if !ok {
+		return &pb.GetNamespacePolicyResponse{}, nil
+	}
+	return &pb.GetNamespacePolicyResponse{NamespacePolicy: clone(policy)}, nil
+}
+
+// GetNamespacePolicies implements the GetNamespacePolicies RPC.
+func (s *Server) GetNamespacePolicies(ctx context.Context, req *pb.GetNamespacePoliciesRequest) (*pb.GetNamespacePoliciesResponse, error) {
+	s.logf("GetNamespacePolicies: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.GetNamespacePoliciesResponse{}
+	for _, name := range sortedKeys(s.namespacePolicies) {
+		resp.NamespacePolicies = append(resp.NamespacePolicies, clone(s.namespacePolicies[name]))
+	}
+	return resp, nil
+}
+
+// CreateTablePolicy implements the CreateTablePolicy RPC.
+func (s *Server) CreateTablePolicy(ctx context.Context, req *pb.CreateTablePolicyRequest) (*pb.CreateTablePolicyResponse, error) {
+	s.logf("CreateTablePolicy: %v", req)
+	if err := requireName("table policy", req.TablePolicyName); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, exists := s.tablePolicies[req.TablePolicyName]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "table policy %s already exists", req.TablePolicyName)
+	}
+	if _, err := s.lookupPolicy(req.PolicyName); err != nil {
+		return nil, err
+	}
+	if _, err := s.lookupTable(req.NamespaceName, req.TableName); err != nil {
+		return nil, err
+	}
+	s.tablePolicies[req.TablePolicyName] = &pb.TablePolicy{
+		Name:          req.TablePolicyName,
+		PolicyName:    req.PolicyName,
+		NamespaceName: req.NamespaceName,
+		TableName:     req.TableName,
+		State:         pb.PolicyState_POLICY_STATE_ENABLED,
+	}
+	return &pb.CreateTablePolicyResponse{}, nil
+}
+
+// setTablePolicyState changes the state of a table policy.
+func (s *Server) setTablePolicyState(name string, state pb.PolicyState) error {
+	s.mu.Lock()
+	defer s.unlock()
+
+	policy, ok := s.tablePolicies[name]
+	if !ok {
+		return status.Errorf(codes.NotFound, "table policy %s does not exist", name)
+	}
+	policy.State = state
+	return nil
+}
+
+// EnableTablePolicy implements the EnableTablePolicy RPC.
+func (s *Server) EnableTablePolicy(ctx context.Context, req *pb.EnableTablePolicyRequest) (*pb.EnableTablePolicyResponse, error) {
+	s.logf("EnableTablePolicy: %v", req)
+	if err := s.setTablePolicyState(req.TablePolicyName, pb.PolicyState_POLICY_STATE_ENABLED); err != nil {
+		return nil, err
+	}
+	return &pb.EnableTablePolicyResponse{}, nil
+}
+
+// DisableTablePolicy implements the DisableTablePolicy RPC.
+func (s *Server) DisableTablePolicy(ctx context.Context, req *pb.DisableTablePolicyRequest) (*pb.DisableTablePolicyResponse, error) {
+	s.logf("DisableTablePolicy: %v", req)
+	if err := s.setTablePolicyState(req.TablePolicyName, pb.PolicyState_POLICY_STATE_DISABLED); err != nil {
+		return nil, err
+	}
+	return &pb.DisableTablePolicyResponse{}, nil
+}
+
+// GetTablePolicy implements the GetTablePolicy RPC. The table policy is left unset if it
+// does not exist.
+func (s *Server) GetTablePolicy(ctx context.Context, req *pb.GetTablePolicyRequest) (*pb.GetTablePolicyResponse, error) {
+	s.logf("GetTablePolicy: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	policy, ok := s.tablePolicies[req.TablePolicyName]
+	if !ok {
+		return &pb.GetTablePolicyResponse{}, nil
+	}
+	return &pb.GetTablePolicyResponse{TablePolicy: clone(policy)}, nil
+}
+
+// GetTablePolicies implements the GetTablePolicies RPC.
+func (s *Server) GetTablePolicies(ctx context.Context, req *pb.GetTablePoliciesRequest) (*pb.GetTablePoliciesResponse, error) {
+	s.logf("GetTablePolicies: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.GetTablePoliciesResponse{}
+	for _, name := range sortedKeys(s.tablePolicies) {
+		resp.TablePolicies = append(resp.TablePolicies, clone(s.tablePolicies[name]))
+	}
+	return resp, nil
+}
@@ -0,0 +1,230 @@
+package mock
+
+import (
+	"context"
+	"strings"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// userContextKey is the context key of the authenticated username.
+type userContextKey struct{}
+
+// RequireAuth makes the server authenticate every request by the auth token in its
+// request header and enforce privileges. Tokens are mapped to users with WithToken or
+// AddToken. Requests that call the Server directly, without gRPC, are not checked.
+func RequireAuth() Option {
+	return func(s *Server) {
+		s.requireAuth = true
+	}
+}
+
+// WithToken maps an auth token to a user.
+func WithToken(token, username string) Option {
+	return func(s *Server) {
+		s.AddToken(token, username)
+	}
+}
+
+// AddToken maps an auth token to a user. The user does not have to exist yet, but
+// requests with the token are rejected while it does not.
+func (s *Server) AddToken(token, username string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[token] = username
+}
+
+// RevokeToken removes an auth token.
+func (s *Server) RevokeToken(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, token)
+}
+
+// currentUser returns the authenticated user of a request, or the default superuser
+// if requests are not authenticated.
+func currentUser(ctx context.Context) string {
+	if username, ok := ctx.Value(userContextKey{}).(string); ok {
+		return username
+	}
+	return defaultUsername
+}
+
+// authRule is what a request requires of an authenticated user. The zero value
+// requires nothing.
+type authRule struct {
+	superuser bool
+	privilege pb.Privilege
+	namespace string
+	table     string
+}
+
+// authRuleOf returns the rule for a request. DDL requests require the privilege on the
+// namespace or table they change, and read-only requests require nothing. All other
+// requests, such as user, privilege and policy management, require a superuser.
+func authRuleOf(req any) authRule {
+	switch req := req.(type) {
+	case *pb.CreateNamespaceRequest:
+		// There is no namespace to hold a privilege yet.
+		return authRule{superuser: true}
+	case *pb.DropNamespaceRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_DROP, namespace: req.NamespaceName}
+	case *pb.RepairNamespaceRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_CREATE, namespace: req.NamespaceName}
+	case *pb.CreateTableRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_CREATE, namespace: req.NamespaceName}
+	case *pb.DropTableRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_DROP, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.TruncateTableRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_TRUNCATE, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.RepairTableRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_CREATE, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.CreateIndexRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_CREATE, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.DropIndexRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_DROP, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.AddNewColumnToTableRequest:
+		return authRule{privilege: pb.Privilege_PRIVILEGE_ALTER, namespace: req.NamespaceName, table: req.TableName}
+	case *pb.NamespaceExistsRequest, *pb.TableExistsRequest, *pb.IndexExistsRequest,
+		*pb.GetTableMetadataRequest, *pb.GetNamespaceNamesRequest, *pb.GetNamespaceTableNamesRequest,
+		*pb.CoordinatorTablesExistRequest, *pb.GetCurrentUserRequest, *pb.GetUserRequest,
+		*pb.GetUsersRequest, *pb.GetPrivilegesRequest:
+		return authRule{}
+	}
+	return authRule{superuser: true}
+}
+
+// dataAuthRules returns the rules for a request of the DistributedTransaction service, or
+// false if it is not one. Reads require READ, writes WRITE and deletes DELETE on every
+// table they access. Beginning and ending transactions require nothing.
+func dataAuthRules(req any) ([]authRule, bool) {
+	on := func(privilege pb.Privilege, namespace, table string) authRule {
+		return authRule{privilege: privilege, namespace: namespace, table: table}
+	}
+	var rules []authRule
+	switch req := req.(type) {
+	case *pb.BeginRequest, *pb.CommitRequest, *pb.RollbackRequest:
+	case *pb.GetRequest:
+		rules = append(rules, on(pb.Privilege_PRIVILEGE_READ, req.GetGet().GetNamespaceName(), req.GetGet().GetTableName()))
+	case *pb.ScanRequest:
+		rules = append(rules, on(pb.Privilege_PRIVILEGE_READ, req.GetScan().GetNamespaceName(), req.GetScan().GetTableName()))
+	case *pb.PutRequest, *pb.InsertRequest, *pb.UpsertRequest, *pb.UpdateRequest, *pb.DeleteRequest, *pb.MutateRequest:
+		var mutations []mutation
+		switch req := req.(type) {
+		case *pb.PutRequest:
+			for _, put := range req.Puts {
+				mutations = append(mutations, putMutation(put))
+			}
+		case *pb.InsertRequest:
+			for _, insert := range req.Inserts {
+				mutations = append(mutations, insertMutation(insert))
+			}
+		case *pb.UpsertRequest:
+			for _, upsert := range req.Upserts {
+				mutations = append(mutations, upsertMutation(upsert))
+			}
+		case *pb.UpdateRequest:
+			for _, update := range req.Updates {
+				mutations = append(mutations, updateMutation(update))
+			}
+		case *pb.DeleteRequest:
+			for _, del := range req.Deletes {
+				mutations = append(mutations, deleteMutation(del))
+			}
+		case *pb.MutateRequest:
+			for _, m := range req.Mutations {
+				if converted, err := mutationOf(m); err == nil {
+					mutations = append(mutations, converted)
+				}
+			}
+		}
+		for _, m := range mutations {
+			privilege := pb.Privilege_PRIVILEGE_WRITE
+			if m.kind == mutationDelete {
+				privilege = pb.Privilege_PRIVILEGE_DELETE
+			}
+			rules = append(rules, on(privilege, m.namespace, m.table))
+			if len(m.expressions) > 0 || m.ifExists || m.ifNotExists || m.kind != mutationDelete {
+				// Conditions and merging with the existing record read it.
+				rules = append(rules, on(pb.Privilege_PRIVILEGE_READ, m.namespace, m.table))
+			}
+		}
+	default:
+		return nil, false
+	}
+	return rules, true
+}
+
+// hasPrivilege reports whether a user holds a privilege on a table or on the namespace
+// that contains it. The caller must hold mu.
+func (s *Server) hasPrivilege(username string, privilege pb.Privilege, namespace, table string) bool {
+	if s.privileges[privilegeTarget{username: username, namespace: namespace}][privilege] {
+		return true
+	}
+	return table != "" && s.privileges[privilegeTarget{username: username, namespace: namespace, table: table}][privilege]
+}
+
+// authorize authenticates a request and checks that its user may make it. It returns
+// the username, or Unauthenticated or PermissionDenied.
+func (s *Server) authorize(method string, req any) (string, error) {
+	header, _ := req.(interface{ GetRequestHeader() *pb.RequestHeader })
+	var token string
+	if header != nil {
+		token = header.GetRequestHeader().GetAuthToken()
+	}
+	if token == "" {
+		return "", status.Errorf(codes.Unauthenticated, "%s requires an auth token", method)
+	}
+
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	username, ok := s.tokens[token]
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "the auth token is invalid")
+	}
+	user, ok := s.users[username]
+	if !ok {
+		return "", status.Errorf(codes.Unauthenticated, "user %s of the auth token does not exist", username)
+	}
+	if user.superuser {
+		return username, nil
+	}
+
+	rules, ok := dataAuthRules(req)
+	if !ok {
+		rules = []authRule{authRuleOf(req)}
+	}
+	for _, rule := range rules {
+		switch {
+		case rule.superuser:
+			return "", status.Errorf(codes.PermissionDenied, "%s requires a superuser, but user %s is not", method, username)
+		case rule.privilege != pb.Privilege_PRIVILEGE_UNSPECIFIED && !s.hasPrivilege(username, rule.privilege, rule.namespace, rule.table):
+			target := "namespace " + rule.namespace
+			if rule.table != "" {
+				target = "table " + rule.namespace + "." + rule.table
+			}
+			return "", status.Errorf(codes.PermissionDenied, "user %s does not have the %s privilege on %s",
+				username, strings.TrimPrefix(rule.privilege.String(), "PRIVILEGE_"), target)
+		}
+	}
+	return username, nil
+}
+
+// authInterceptor authenticates and authorizes a request if the server requires auth,
+// and passes the username to the handler in the context.
+func (s *Server) authInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	if !s.requireAuth {
+		return handler(ctx, req)
+	}
+	method := rpcName(info)
+	username, err := s.authorize(method, req)
+	if err != nil {
+		s.logf("%s: %v", method, err)
+		return nil, err
+	}
+	return handler(context.WithValue(ctx, userContextKey{}, username), req)
+}
@@ -0,0 +1,204 @@
+package mock
+
+import (
+	"context"
+	"testing"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestAuth(t *testing.T) {
+	server := ListenBufconn(WithLogger(nil), RequireAuth(), WithToken("admin-token", "admin"), WithToken("alice-token", "alice"))
+	t.Cleanup(server.Stop)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewDistributedTransactionAdminClient(conn)
+	ctx := context.Background()
+
+	// Direct calls are not authenticated.
+	if _, err := server.CreateUser(ctx, &pb.CreateUserRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+
+	header := func(token string) *pb.RequestHeader {
+		if token == "" {
+			return nil
+		}
+		return &pb.RequestHeader{AuthToken: &token}
+	}
+	createTable := func(token, table string) error {
+		_, err := client.CreateTable(ctx, &pb.CreateTableRequest{
+			RequestHeader: header(token), NamespaceName: "ns", TableName: table, TableMetadata: usersMetadata(),
+		})
+		return err
+	}
+	dropTable := func(token, table string) error {
+		_, err := client.DropTable(ctx, &pb.DropTableRequest{RequestHeader: header(token), NamespaceName: "ns", TableName: table})
+		return err
+	}
+	grant := func(privilege pb.Privilege, table *string) {
+		t.Helper()
+		if _, err := server.Grant(ctx, &pb.GrantRequest{
+			Username: "alice", NamespaceName: "ns", TableName: table, Privileges: []pb.Privilege{privilege},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	users := "users"
+
+	tests := []struct {
+		name  string
+		setup func()
+		call  func() error
+		want  codes.Code
+	}{
+		{
+			name: "no token",
+			call: func() error { return createTable("", "users") },
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "unknown token",
+			call: func() error { return createTable("bob-token", "users") },
+			want: codes.Unauthenticated,
+		},
+		{
+			name: "read without privileges",
+			call: func() error {
+				_, err := client.NamespaceExists(ctx, &pb.NamespaceExistsRequest{RequestHeader: header("alice-token"), NamespaceName: "ns"})
+				return err
+			},
+			want: codes.OK,
+		},
+		{
+			name: "create table without CREATE",
+			call: func() error { return createTable("alice-token", "users") },
+			want: codes.PermissionDenied,
+		},
+		{
+			name:  "create table with CREATE on the namespace",
+			setup: func() { grant(pb.Privilege_PRIVILEGE_CREATE, nil) },
+			call:  func() error { return createTable("alice-token", "users") },
+			want:  codes.OK,
+		},
+		{
+			name: "drop table without DROP",
+			call: func() error { return dropTable("alice-token", "users") },
+			want: codes.PermissionDenied,
+		},
+		{
+			name:  "drop table with DROP on the table",
+			setup: func() { grant(pb.Privilege_PRIVILEGE_DROP, &users) },
+			call:  func() error { return dropTable("alice-token", "users") },
+			want:  codes.OK,
+		},
+		{
+			name: "create namespace as a non-superuser",
+			call: func() error {
+				_, err := client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{RequestHeader: header("alice-token"), NamespaceName: "other"})
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+		{
+			name: "create user as a non-superuser",
+			call: func() error {
+				_, err := client.CreateUser(ctx, &pb.CreateUserRequest{RequestHeader: header("alice-token"), Username: "bob"})
+				return err
+			},
+			want: codes.PermissionDenied,
+		},
+		{
+			name: "superuser",
+			call: func() error { return createTable("admin-token", "orders") },
+			want: codes.OK,
+		},
+		{
+			name:  "revoked token",
+			setup: func() { server.RevokeToken("admin-token") },
+			call:  func() error { return createTable("admin-token", "items") },
+			want:  codes.Unauthenticated,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.setup != nil {
+				tt.setup()
+			}
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+
+	resp, err := client.GetCurrentUser(ctx, &pb.GetCurrentUserRequest{RequestHeader: header("alice-token")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if resp.User.Name != "alice" || resp.User.Superuser {
+		t.Errorf("got current user %+v, want alice", resp.User)
+	}
+}
+
+func TestDataAuth(t *testing.T) {
+	server := ListenBufconn(WithLogger(nil), RequireAuth(), WithToken("alice-token", "alice"))
+	t.Cleanup(server.Stop)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewDistributedTransactionClient(conn)
+	ctx := context.Background()
+
+	if _, err := server.CreateUser(ctx, &pb.CreateUserRequest{Username: "alice"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: "users", TableMetadata: usersMetadata()}); err != nil {
+		t.Fatal(err)
+	}
+	token := "alice-token"
+	header := &pb.RequestHeader{AuthToken: &token}
+	insert := func() error {
+		_, err := client.Insert(ctx, &pb.InsertRequest{RequestHeader: header, Inserts: []*pb.Insert{{
+			NamespaceName: "ns", TableName: "users", PartitionKey: key(intColumn("id", 1)),
+		}}})
+		return err
+	}
+	scan := func() error {
+		_, err := client.Scan(ctx, &pb.ScanRequest{RequestHeader: header, Scan: &pb.Scan{
+			NamespaceName: "ns", TableName: "users", ScanType: pb.Scan_SCAN_TYPE_SCAN_ALL,
+		}})
+		return err
+	}
+
+	if err := scan(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("scan without READ: got %v, want PermissionDenied", err)
+	}
+	if _, err := server.Grant(ctx, &pb.GrantRequest{Username: "alice", NamespaceName: "ns", Privileges: []pb.Privilege{pb.Privilege_PRIVILEGE_READ}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := scan(); err != nil {
+		t.Errorf("scan with READ: %v", err)
+	}
+	if err := insert(); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("insert without WRITE: got %v, want PermissionDenied", err)
+	}
+	if _, err := server.Grant(ctx, &pb.GrantRequest{Username: "alice", NamespaceName: "ns", Privileges: []pb.Privilege{pb.Privilege_PRIVILEGE_WRITE}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := insert(); err != nil {
+		t.Errorf("insert with WRITE: %v", err)
+	}
+}
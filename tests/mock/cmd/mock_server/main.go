@@ -0,0 +1,66 @@
+// Command mock_server runs the in-memory ScalarDB Cluster admin emulator.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/scalar-labs/terraform-provider-scalardb/tests/mock"
+)
+
+var (
+	port      = flag.Int("port", 60051, "The server port")
+	stateFile = flag.String("state-file", "", "A JSON snapshot to load on start and to write after every change")
+)
+
+func main() {
+	var opts []mock.Option
+	flag.Func("token", "Require auth and map an auth token to a user, as token=username. Can be repeated", func(value string) error {
+		token, username, ok := strings.Cut(value, "=")
+		if !ok || token == "" || username == "" {
+			return errors.New("token must be given as token=username")
+		}
+		opts = append(opts, mock.RequireAuth(), mock.WithToken(token, username))
+		return nil
+	})
+	flag.Parse()
+
+	// Check if port is specified via environment variable
+	if portEnv := os.Getenv("SCALARDB_MOCK_PORT"); portEnv != "" {
+		if p, err := strconv.Atoi(portEnv); err == nil {
+			*port = p
+		}
+	}
+
+	if *stateFile != "" {
+		opts = append(opts, mock.WithStateFile(*stateFile))
+	}
+	server := mock.NewServer(opts...)
+	if *stateFile != "" {
+		switch err := server.Load(*stateFile); {
+		case errors.Is(err, fs.ErrNotExist):
+			log.Printf("State file %s does not exist, starting empty", *stateFile)
+		case err != nil:
+			log.Fatalf("failed to load state: %v", err)
+		default:
+			log.Printf("Loaded state from %s", *stateFile)
+		}
+	}
+
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
+	if err != nil {
+		log.Fatalf("failed to listen: %v", err)
+	}
+	instance := server.Serve(listener)
+	log.Printf("Mock ScalarDB Cluster server listening at %v", instance.Addr())
+	if err := instance.Wait(); err != nil {
+		log.Fatalf("failed to serve: %v", err)
+	}
+}
@@ -0,0 +1,162 @@
+package mock
+
+import (
+	"context"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Fault scripts how calls to an RPC misbehave. Faults are counted per RPC from the
+// moment they are injected, and the zero value lets every call through.
+type Fault struct {
+	// Skip is the number of calls that are let through before the fault applies.
+	Skip int
+	// Times is the number of calls the fault applies to. Zero applies it to every call after Skip.
+	Times int
+	// Latency delays the response. A call whose context is done while it is delayed fails
+	// with the context's status.
+	Latency time.Duration
+	// Code is the status code returned instead of the response. codes.OK returns the response.
+	Code codes.Code
+	// AfterHandler applies the fault after the request has been handled, so the change is
+	// made server-side while the client sees the error.
+	AfterHandler bool
+}
+
+// Unavailable returns a fault that fails the next n calls with Unavailable.
+func Unavailable(n int) Fault {
+	return Fault{Times: n, Code: codes.Unavailable}
+}
+
+// FailNth returns a fault that fails only the nth call, counted from 1, with a code.
+func FailNth(n int, code codes.Code) Fault {
+	return Fault{Skip: n - 1, Times: 1, Code: code}
+}
+
+// Delay returns a fault that delays every call by d.
+func Delay(d time.Duration) Fault {
+	return Fault{Latency: d}
+}
+
+// LostResponse returns a fault that handles the next call and then fails it with
+// DeadlineExceeded, as if the client had timed out before the response arrived.
+func LostResponse() Fault {
+	return Fault{Times: 1, Code: codes.DeadlineExceeded, AfterHandler: true}
+}
+
+// injectedFault is a fault with the number of calls it has seen.
+type injectedFault struct {
+	Fault
+	calls int
+}
+
+// applies reports whether the fault applies to its latest call.
+func (f *injectedFault) applies() bool {
+	return f.calls > f.Skip && (f.Times == 0 || f.calls <= f.Skip+f.Times)
+}
+
+// exhausted reports whether the fault will not apply to any further call.
+func (f *injectedFault) exhausted() bool {
+	return f.Times != 0 && f.calls >= f.Skip+f.Times
+}
+
+// WithFault injects a fault into an RPC from the start.
+func WithFault(method string, fault Fault) Option {
+	return func(s *Server) {
+		s.InjectFault(method, fault)
+	}
+}
+
+// InjectFault scripts a fault for an RPC, named without its service such as "CreateTable".
+// When several faults apply to the same call, the one injected first is used.
+func (s *Server) InjectFault(method string, fault Fault) {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	s.faults[method] = append(s.faults[method], &injectedFault{Fault: fault})
+}
+
+// ClearFaults removes all injected faults.
+func (s *Server) ClearFaults() {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	clear(s.faults)
+}
+
+// Calls returns the number of calls an RPC has received, including failed ones.
+func (s *Server) Calls(method string) int {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	return s.calls[method]
+}
+
+// nextFault counts a call to an RPC and returns the fault that applies to it, if any.
+func (s *Server) nextFault(method string) (Fault, bool) {
+	s.faultMu.Lock()
+	defer s.faultMu.Unlock()
+	s.calls[method]++
+
+	var fault Fault
+	var found bool
+	remaining := s.faults[method][:0]
+	for _, f := range s.faults[method] {
+		f.calls++
+		if !found && f.applies() {
+			fault, found = f.Fault, true
+		}
+		if !f.exhausted() {
+			remaining = append(remaining, f)
+		}
+	}
+	s.faults[method] = remaining
+	return fault, found
+}
+
+// faultInterceptor applies the injected faults to a request.
+func (s *Server) faultInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	method := rpcName(info)
+	fault, ok := s.nextFault(method)
+	if !ok {
+		return handler(ctx, req)
+	}
+	s.logf("%s: injecting fault %+v", method, fault)
+
+	if !fault.AfterHandler {
+		if err := sleep(ctx, fault.Latency); err != nil {
+			return nil, err
+		}
+		if fault.Code != codes.OK {
+			return nil, status.Errorf(fault.Code, "injected fault on %s", method)
+		}
+		return handler(ctx, req)
+	}
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if err := sleep(ctx, fault.Latency); err != nil {
+		return nil, err
+	}
+	if fault.Code != codes.OK {
+		return nil, status.Errorf(fault.Code, "injected fault on %s after it was handled", method)
+	}
+	return resp, nil
+}
+
+// sleep waits for d, or returns the status of the context if it is done first.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return status.FromContextError(ctx.Err()).Err()
+	}
+}
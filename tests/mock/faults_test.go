@@ -0,0 +1,110 @@
+package mock
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestFaults(t *testing.T) {
+	tests := []struct {
+		name  string
+		fault Fault
+		want  []codes.Code
+	}{
+		{
+			name:  "unavailable twice",
+			fault: Unavailable(2),
+			want:  []codes.Code{codes.Unavailable, codes.Unavailable, codes.OK, codes.OK},
+		},
+		{
+			name:  "fail the third call",
+			fault: FailNth(3, codes.Internal),
+			want:  []codes.Code{codes.OK, codes.OK, codes.Internal, codes.OK},
+		},
+		{
+			name:  "fail every call",
+			fault: Fault{Code: codes.ResourceExhausted},
+			want:  []codes.Code{codes.ResourceExhausted, codes.ResourceExhausted, codes.ResourceExhausted},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := ListenBufconn(WithLogger(nil), WithFault("NamespaceExists", tt.fault))
+			t.Cleanup(server.Stop)
+			conn, err := server.Dial()
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { conn.Close() })
+			client := pb.NewDistributedTransactionAdminClient(conn)
+
+			for i, want := range tt.want {
+				_, err := client.NamespaceExists(context.Background(), &pb.NamespaceExistsRequest{NamespaceName: "ns"})
+				if got := status.Code(err); got != want {
+					t.Errorf("call %d: got %s, want %s", i+1, got, want)
+				}
+			}
+			if got := server.Calls("NamespaceExists"); got != len(tt.want) {
+				t.Errorf("got %d calls, want %d", got, len(tt.want))
+			}
+		})
+	}
+}
+
+func TestFaultLatency(t *testing.T) {
+	server := ListenBufconn(WithLogger(nil), WithFault("NamespaceExists", Delay(time.Second)))
+	t.Cleanup(server.Stop)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewDistributedTransactionAdminClient(conn)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = client.NamespaceExists(ctx, &pb.NamespaceExistsRequest{NamespaceName: "ns"})
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Errorf("got %s, want %s", got, codes.DeadlineExceeded)
+	}
+
+	server.ClearFaults()
+	if _, err := client.NamespaceExists(context.Background(), &pb.NamespaceExistsRequest{NamespaceName: "ns"}); err != nil {
+		t.Errorf("got %v after clearing faults", err)
+	}
+}
+
+func TestLostResponse(t *testing.T) {
+	server := ListenBufconn(WithLogger(nil))
+	t.Cleanup(server.Stop)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	client := pb.NewDistributedTransactionAdminClient(conn)
+	ctx := context.Background()
+
+	server.InjectFault("CreateNamespace", LostResponse())
+	_, err = client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"})
+	if got := status.Code(err); got != codes.DeadlineExceeded {
+		t.Fatalf("got %s, want %s", got, codes.DeadlineExceeded)
+	}
+
+	resp, err := client.NamespaceExists(ctx, &pb.NamespaceExistsRequest{NamespaceName: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !resp.Exists {
+		t.Error("namespace was not created server-side")
+	}
+	_, err = client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"})
+	if got := status.Code(err); got != codes.AlreadyExists {
+		t.Errorf("got %s on retry, want %s", got, codes.AlreadyExists)
+	}
+}
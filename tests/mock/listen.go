@@ -0,0 +1,138 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+// bufconnSize is the buffer size of in-process bufconn listeners.
+const bufconnSize = 1 << 20
+
+// Instance is a Server that is serving gRPC requests on a listener.
+type Instance struct {
+	*Server
+
+	grpcServer *grpc.Server
+	health     *health.Server
+	listener   net.Listener
+	bufconn    *bufconn.Listener
+	done       chan error
+}
+
+// Serve starts serving a new Server on a listener in the background.
+// The listener is closed when the instance is stopped.
+func Serve(listener net.Listener, opts ...Option) *Instance {
+	return NewServer(opts...).Serve(listener)
+}
+
+// Serve starts serving the server on a listener in the background.
+// The listener is closed when the instance is stopped.
+func (s *Server) Serve(listener net.Listener) *Instance {
+	i := &Instance{
+		Server:     s,
+		grpcServer: grpc.NewServer(grpc.UnaryInterceptor(s.UnaryInterceptor())),
+		health:     health.NewServer(),
+		listener:   listener,
+		done:       make(chan error, 1),
+	}
+	pb.RegisterDistributedTransactionAdminServer(i.grpcServer, s)
+	pb.RegisterDistributedTransactionServer(i.grpcServer, s.Transactions())
+	healthpb.RegisterHealthServer(i.grpcServer, i.health)
+	// Register reflection service on gRPC server.
+	reflection.Register(i.grpcServer)
+
+	go func() {
+		i.done <- i.grpcServer.Serve(listener)
+	}()
+	return i
+}
+
+// Listen starts a new Server on a TCP address. Use "127.0.0.1:0" for an ephemeral port.
+func Listen(address string, opts ...Option) (*Instance, error) {
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", address, err)
+	}
+	return Serve(listener, opts...), nil
+}
+
+// ListenBufconn starts a new Server on an in-process bufconn listener.
+// Clients must connect with Dial or DialOption.
+func ListenBufconn(opts ...Option) *Instance {
+	listener := bufconn.Listen(bufconnSize)
+	i := Serve(listener, opts...)
+	i.bufconn = listener
+	return i
+}
+
+// Addr returns the address the instance is listening on.
+func (i *Instance) Addr() net.Addr {
+	return i.listener.Addr()
+}
+
+// Port returns the TCP port the instance is listening on, or 0 for a bufconn listener.
+func (i *Instance) Port() int {
+	if addr, ok := i.listener.Addr().(*net.TCPAddr); ok {
+		return addr.Port
+	}
+	return 0
+}
+
+// DialOption returns the dial option that connects to the instance. For a TCP listener
+// it is a no-op, so the target address must be the instance's address.
+func (i *Instance) DialOption() grpc.DialOption {
+	if i.bufconn == nil {
+		return grpc.EmptyDialOption{}
+	}
+	return grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+		return i.bufconn.DialContext(ctx)
+	})
+}
+
+// Dial returns an insecure client connection to the instance.
+func (i *Instance) Dial() (*grpc.ClientConn, error) {
+	target := i.Addr().String()
+	if i.bufconn != nil {
+		target = "passthrough:///bufnet"
+	}
+	return grpc.NewClient(target,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		i.DialOption(),
+	)
+}
+
+// Wait blocks until the instance stops serving and returns the error that stopped it,
+// or nil if it was stopped with Stop.
+func (i *Instance) Wait() error {
+	err := <-i.done
+	i.done <- err
+	if err == grpc.ErrServerStopped {
+		return nil
+	}
+	return err
+}
+
+// SetServing sets whether the instance reports SERVING or NOT_SERVING to gRPC health
+// checks. It keeps serving requests either way, but clients that check health stop
+// sending requests to it while it is not serving. Instances start serving.
+func (i *Instance) SetServing(serving bool) {
+	if serving {
+		i.health.Resume()
+	} else {
+		i.health.Shutdown()
+	}
+}
+
+// Stop stops serving immediately, closing the listener and all open connections.
+func (i *Instance) Stop() {
+	i.grpcServer.Stop()
+}
@@ -1,397 +1,585 @@
-package main
+// Package mock provides an in-memory emulator of the ScalarDB Cluster admin API and
+// DistributedTransaction service.
+// It can be run as a standalone server (see cmd/mock_server) or started from a Go test
+// on an ephemeral port or an in-process bufconn listener.
+package mock
 
 import (
 	"context"
-	"flag"
-	"fmt"
 	"log"
-	"net"
-	"os"
-	"strconv"
+	"path"
+	"slices"
+	"sort"
+	"sync"
 
 	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
 )
 
-var (
-	port = flag.Int("port", 60051, "The server port")
-)
-
-// mockServer is used to implement ScalarDB Cluster gRPC API.
-type mockServer struct {
+// Server implements the ScalarDB Cluster admin gRPC API.
+// It emulates the admin API in memory. All state is guarded by mu, and messages are
+// cloned on the way in and out so that stored state is never shared with a request.
+// With WithStateFile, the state is also written to a snapshot after every change.
+// Records written through the DistributedTransaction service (see Transactions) share
+// mu but are not part of the snapshot.
+type Server struct {
 	pb.UnimplementedDistributedTransactionAdminServer
-	namespaces map[string]bool
-	tables     map[string]map[string]*pb.TableMetadata
+
+	logger      *log.Logger
+	requireAuth bool
+	stateFile   string
+
+	mu                sync.RWMutex
+	namespaces        map[string]bool
+	unlisted          map[string]bool
+	tables            map[string]map[string]*pb.TableMetadata
+	coordinatorTables bool
+	users             map[string]*mockUser
+	privileges        map[privilegeTarget]map[pb.Privilege]bool
+	policies          map[string]*abacPolicy
+	namespacePolicies map[string]*pb.NamespacePolicy
+	tablePolicies     map[string]*pb.TablePolicy
+	tokens            map[string]string
+
+	// records are the versions of the records of each table by primary key, read and
+	// written by the DistributedTransaction service.
+	records           map[tableName]map[string][]recordVersion
+	transactions      map[string]*transaction
+	clock             uint64
+	lastTransactionID uint64
+
+	faultMu sync.Mutex
+	faults  map[string][]*injectedFault
+	calls   map[string]int
+}
+
+// Option configures a Server.
+type Option func(*Server)
+
+// WithLogger sets the logger that requests are logged to. A nil logger disables logging.
+// Defaults to the standard logger.
+func WithLogger(logger *log.Logger) Option {
+	return func(s *Server) {
+		s.logger = logger
+	}
 }
 
-func newMockServer() *mockServer {
-	return &mockServer{
+// NewServer creates an empty server with the default superuser.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		logger:     log.Default(),
 		namespaces: make(map[string]bool),
+		unlisted:   make(map[string]bool),
 		tables:     make(map[string]map[string]*pb.TableMetadata),
+		users: map[string]*mockUser{
+			defaultUsername: {superuser: true},
+		},
+		privileges:        make(map[privilegeTarget]map[pb.Privilege]bool),
+		policies:          make(map[string]*abacPolicy),
+		namespacePolicies: make(map[string]*pb.NamespacePolicy),
+		tablePolicies:     make(map[string]*pb.TablePolicy),
+		tokens:            make(map[string]string),
+		records:           make(map[tableName]map[string][]recordVersion),
+		transactions:      make(map[string]*transaction),
+		faults:            make(map[string][]*injectedFault),
+		calls:             make(map[string]int),
+	}
+	for _, opt := range opts {
+		opt(s)
 	}
+	return s
 }
 
-// CreateNamespace implements the CreateNamespace RPC.
-func (s *mockServer) CreateNamespace(ctx context.Context, req *pb.CreateNamespaceRequest) (*pb.CreateNamespaceResponse, error) {
-	log.Printf("CreateNamespace: %v", req)
-	if _, exists := s.namespaces[req.NamespaceName]; exists && !req.IfNotExists {
-		return nil, fmt.Errorf("namespace %s already exists", req.NamespaceName)
+// UnaryInterceptor returns the interceptor that applies injected faults and enforces auth.
+// Serve installs it; a Server registered on another gRPC server needs it for these to take effect.
+func (s *Server) UnaryInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return s.faultInterceptor(ctx, req, info, func(ctx context.Context, req any) (any, error) {
+			return s.authInterceptor(ctx, req, info, handler)
+		})
 	}
-	s.namespaces[req.NamespaceName] = true
-	if _, exists := s.tables[req.NamespaceName]; !exists {
-		s.tables[req.NamespaceName] = make(map[string]*pb.TableMetadata)
-	}
-	return &pb.CreateNamespaceResponse{}, nil
 }
 
-// DropNamespace implements the DropNamespace RPC.
-func (s *mockServer) DropNamespace(ctx context.Context, req *pb.DropNamespaceRequest) (*pb.DropNamespaceResponse, error) {
-	log.Printf("DropNamespace: %v", req)
-	if _, exists := s.namespaces[req.NamespaceName]; !exists && !req.IfExists {
-		return nil, fmt.Errorf("namespace %s does not exist", req.NamespaceName)
-	}
-	delete(s.namespaces, req.NamespaceName)
-	delete(s.tables, req.NamespaceName)
-	return &pb.DropNamespaceResponse{}, nil
+// rpcName returns the name of an RPC without its service, such as "CreateTable".
+func rpcName(info *grpc.UnaryServerInfo) string {
+	return path.Base(info.FullMethod)
 }
 
-// NamespaceExists implements the NamespaceExists RPC.
-func (s *mockServer) NamespaceExists(ctx context.Context, req *pb.NamespaceExistsRequest) (*pb.NamespaceExistsResponse, error) {
-	log.Printf("NamespaceExists: %v", req)
-	exists := false
-	if _, ok := s.namespaces[req.NamespaceName]; ok {
-		exists = true
+// logf logs a request if logging is enabled.
+func (s *Server) logf(format string, args ...any) {
+	if s.logger != nil {
+		s.logger.Printf(format, args...)
 	}
-	return &pb.NamespaceExistsResponse{
-		Exists: exists,
-	}, nil
 }
 
-// CreateTable implements the CreateTable RPC.
-func (s *mockServer) CreateTable(ctx context.Context, req *pb.CreateTableRequest) (*pb.CreateTableResponse, error) {
-	log.Printf("CreateTable: %v", req)
-	if _, exists := s.namespaces[req.NamespaceName]; !exists {
-		return nil, fmt.Errorf("namespace %s does not exist", req.NamespaceName)
-	}
-	if _, exists := s.tables[req.NamespaceName][req.TableName]; exists && !req.IfNotExists {
-		return nil, fmt.Errorf("table %s.%s already exists", req.NamespaceName, req.TableName)
-	}
-	s.tables[req.NamespaceName][req.TableName] = req.TableMetadata
-	return &pb.CreateTableResponse{}, nil
+// clone returns a deep copy of a message.
+func clone[T proto.Message](m T) T {
+	return proto.Clone(m).(T)
 }
 
-// DropTable implements the DropTable RPC.
-func (s *mockServer) DropTable(ctx context.Context, req *pb.DropTableRequest) (*pb.DropTableResponse, error) {
-	log.Printf("DropTable: %v", req)
-	if _, exists := s.namespaces[req.NamespaceName]; !exists {
-		return nil, fmt.Errorf("namespace %s does not exist", req.NamespaceName)
-	}
-	if _, exists := s.tables[req.NamespaceName][req.TableName]; !exists && !req.IfExists {
-		return nil, fmt.Errorf("table %s.%s does not exist", req.NamespaceName, req.TableName)
+// requireName returns InvalidArgument if a name is empty.
+func requireName(kind, name string) error {
+	if name == "" {
+		return status.Errorf(codes.InvalidArgument, "%s name must not be empty", kind)
 	}
-	delete(s.tables[req.NamespaceName], req.TableName)
-	return &pb.DropTableResponse{}, nil
+	return nil
 }
 
-// TableExists implements the TableExists RPC.
-func (s *mockServer) TableExists(ctx context.Context, req *pb.TableExistsRequest) (*pb.TableExistsResponse, error) {
-	log.Printf("TableExists: %v", req)
-	exists := false
-	if _, ok := s.namespaces[req.NamespaceName]; ok {
-		if _, ok := s.tables[req.NamespaceName][req.TableName]; ok {
-			exists = true
-		}
+// namespaceNotFound returns NotFound unless the namespace exists. The caller must hold mu.
+func (s *Server) namespaceNotFound(namespace string) error {
+	if !s.namespaces[namespace] {
+		return status.Errorf(codes.NotFound, "namespace %s does not exist", namespace)
 	}
-	return &pb.TableExistsResponse{
-		Exists: exists,
-	}, nil
+	return nil
 }
 
-// GetTableMetadata implements the GetTableMetadata RPC.
-func (s *mockServer) GetTableMetadata(ctx context.Context, req *pb.GetTableMetadataRequest) (*pb.GetTableMetadataResponse, error) {
-	log.Printf("GetTableMetadata: %v", req)
-	if _, exists := s.namespaces[req.NamespaceName]; !exists {
-		return nil, fmt.Errorf("namespace %s does not exist", req.NamespaceName)
+// lookupTable returns the metadata of a table, or NotFound if the namespace or the
+// table does not exist. The caller must hold mu.
+func (s *Server) lookupTable(namespace, table string) (*pb.TableMetadata, error) {
+	if err := s.namespaceNotFound(namespace); err != nil {
+		return nil, err
 	}
-	if _, exists := s.tables[req.NamespaceName][req.TableName]; !exists {
-		return nil, fmt.Errorf("table %s.%s does not exist", req.NamespaceName, req.TableName)
+	metadata, ok := s.tables[namespace][table]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "table %s.%s does not exist", namespace, table)
 	}
-	return &pb.GetTableMetadataResponse{
-		TableMetadata: s.tables[req.NamespaceName][req.TableName],
-	}, nil
-}
-
-// Implement other required methods from the DistributedTransactionAdmin interface
-// with empty implementations to satisfy the interface.
-
-func (s *mockServer) TruncateTable(ctx context.Context, req *pb.TruncateTableRequest) (*pb.TruncateTableResponse, error) {
-	return &pb.TruncateTableResponse{}, nil
-}
-
-func (s *mockServer) CreateIndex(ctx context.Context, req *pb.CreateIndexRequest) (*pb.CreateIndexResponse, error) {
-	return &pb.CreateIndexResponse{}, nil
-}
-
-func (s *mockServer) DropIndex(ctx context.Context, req *pb.DropIndexRequest) (*pb.DropIndexResponse, error) {
-	return &pb.DropIndexResponse{}, nil
+	return metadata, nil
 }
 
-func (s *mockServer) IndexExists(ctx context.Context, req *pb.IndexExistsRequest) (*pb.IndexExistsResponse, error) {
-	return &pb.IndexExistsResponse{Exists: false}, nil
-}
-
-func (s *mockServer) RepairNamespace(ctx context.Context, req *pb.RepairNamespaceRequest) (*pb.RepairNamespaceResponse, error) {
-	return &pb.RepairNamespaceResponse{}, nil
-}
-
-func (s *mockServer) RepairTable(ctx context.Context, req *pb.RepairTableRequest) (*pb.RepairTableResponse, error) {
-	return &pb.RepairTableResponse{}, nil
-}
-
-func (s *mockServer) AddNewColumnToTable(ctx context.Context, req *pb.AddNewColumnToTableRequest) (*pb.AddNewColumnToTableResponse, error) {
-	return &pb.AddNewColumnToTableResponse{}, nil
-}
-
-func (s *mockServer) CreateCoordinatorTables(ctx context.Context, req *pb.CreateCoordinatorTablesRequest) (*pb.CreateCoordinatorTablesResponse, error) {
-	return &pb.CreateCoordinatorTablesResponse{}, nil
-}
-
-func (s *mockServer) DropCoordinatorTables(ctx context.Context, req *pb.DropCoordinatorTablesRequest) (*pb.DropCoordinatorTablesResponse, error) {
-	return &pb.DropCoordinatorTablesResponse{}, nil
-}
+// validateTableMetadata checks that the keys, orders, indexes and encrypted columns of
+// a table refer to its columns.
+func validateTableMetadata(metadata *pb.TableMetadata) error {
+	if metadata == nil || len(metadata.Columns) == 0 {
+		return status.Error(codes.InvalidArgument, "table metadata must have at least one column")
+	}
+	if len(metadata.PartitionKeyColumnNames) == 0 {
+		return status.Error(codes.InvalidArgument, "table metadata must have at least one partition key column")
+	}
+	for name, dataType := range metadata.Columns {
+		if dataType == pb.DataType_DATA_TYPE_UNSPECIFIED {
+			return status.Errorf(codes.InvalidArgument, "the data type of column %s is not specified", name)
+		}
+	}
 
-func (s *mockServer) TruncateCoordinatorTables(ctx context.Context, req *pb.TruncateCoordinatorTablesRequest) (*pb.TruncateCoordinatorTablesResponse, error) {
-	return &pb.TruncateCoordinatorTablesResponse{}, nil
+	lists := map[string][]string{
+		"partition key":   metadata.PartitionKeyColumnNames,
+		"clustering key":  metadata.ClusteringKeyColumnNames,
+		"secondary index": metadata.SecondaryIndexColumnNames,
+		"encrypted":       metadata.EncryptedColumns,
+	}
+	for kind, names := range lists {
+		for _, name := range names {
+			if _, ok := metadata.Columns[name]; !ok {
+				return status.Errorf(codes.InvalidArgument, "%s column %s is not defined", kind, name)
+			}
+		}
+	}
+	for name := range metadata.ClusteringOrders {
+		if !slices.Contains(metadata.ClusteringKeyColumnNames, name) {
+			return status.Errorf(codes.InvalidArgument, "clustering order is given for %s, which is not a clustering key column", name)
+		}
+	}
+	return nil
 }
 
-func (s *mockServer) CoordinatorTablesExist(ctx context.Context, req *pb.CoordinatorTablesExistRequest) (*pb.CoordinatorTablesExistResponse, error) {
-	return &pb.CoordinatorTablesExistResponse{Exist: false}, nil
-}
+// CreateNamespace implements the CreateNamespace RPC.
+func (s *Server) CreateNamespace(ctx context.Context, req *pb.CreateNamespaceRequest) (*pb.CreateNamespaceResponse, error) {
+	s.logf("CreateNamespace: %v", req)
+	if err := requireName("namespace", req.NamespaceName); err != nil {
+		return nil, err
+	}
 
-func (s *mockServer) RepairCoordinatorTables(ctx context.Context, req *pb.RepairCoordinatorTablesRequest) (*pb.RepairCoordinatorTablesResponse, error) {
-	return &pb.RepairCoordinatorTablesResponse{}, nil
-}
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) GetNamespaceNames(ctx context.Context, req *pb.GetNamespaceNamesRequest) (*pb.GetNamespaceNamesResponse, error) {
-	namespaceNames := make([]string, 0, len(s.namespaces))
-	for name := range s.namespaces {
-		namespaceNames = append(namespaceNames, name)
+	if s.namespaces[req.NamespaceName] {
+		if req.IfNotExists {
+			return &pb.CreateNamespaceResponse{}, nil
+		}
+		return nil, status.Errorf(codes.AlreadyExists, "namespace %s already exists", req.NamespaceName)
 	}
-	return &pb.GetNamespaceNamesResponse{NamespaceNames: namespaceNames}, nil
+	s.namespaces[req.NamespaceName] = true
+	s.tables[req.NamespaceName] = make(map[string]*pb.TableMetadata)
+	delete(s.unlisted, req.NamespaceName)
+	return &pb.CreateNamespaceResponse{}, nil
 }
 
-func (s *mockServer) GetNamespaceTableNames(ctx context.Context, req *pb.GetNamespaceTableNamesRequest) (*pb.GetNamespaceTableNamesResponse, error) {
-	if _, exists := s.namespaces[req.NamespaceName]; !exists {
-		return nil, fmt.Errorf("namespace %s does not exist", req.NamespaceName)
+// DropNamespace implements the DropNamespace RPC.
+func (s *Server) DropNamespace(ctx context.Context, req *pb.DropNamespaceRequest) (*pb.DropNamespaceResponse, error) {
+	s.logf("DropNamespace: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	if !s.namespaces[req.NamespaceName] {
+		if req.IfExists {
+			return &pb.DropNamespaceResponse{}, nil
+		}
+		return nil, status.Errorf(codes.NotFound, "namespace %s does not exist", req.NamespaceName)
 	}
-	tableNames := make([]string, 0, len(s.tables[req.NamespaceName]))
-	for name := range s.tables[req.NamespaceName] {
-		tableNames = append(tableNames, name)
+	if len(s.tables[req.NamespaceName]) > 0 {
+		return nil, status.Errorf(codes.InvalidArgument, "namespace %s is not empty", req.NamespaceName)
 	}
-	return &pb.GetNamespaceTableNamesResponse{TableNames: tableNames}, nil
+	delete(s.namespaces, req.NamespaceName)
+	delete(s.unlisted, req.NamespaceName)
+	delete(s.tables, req.NamespaceName)
+	s.dropPrivileges(func(target privilegeTarget) bool { return target.namespace == req.NamespaceName })
+	return &pb.DropNamespaceResponse{}, nil
 }
 
-func (s *mockServer) ImportTable(ctx context.Context, req *pb.ImportTableRequest) (*pb.ImportTableResponse, error) {
-	return &pb.ImportTableResponse{}, nil
+// UnlistNamespace removes a namespace from the namespace metadata, as if the metadata had
+// drifted from the storage. The namespace still exists, but GetNamespaceNames does not list
+// it until it is repaired with RepairNamespace.
+func (s *Server) UnlistNamespace(name string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.namespaces[name] {
+		s.unlisted[name] = true
+	}
 }
 
-func (s *mockServer) Upgrade(ctx context.Context, req *pb.UpgradeRequest) (*pb.UpgradeResponse, error) {
-	return &pb.UpgradeResponse{}, nil
-}
+// NamespaceExists implements the NamespaceExists RPC.
+func (s *Server) NamespaceExists(ctx context.Context, req *pb.NamespaceExistsRequest) (*pb.NamespaceExistsResponse, error) {
+	s.logf("NamespaceExists: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
-	return &pb.CreateUserResponse{}, nil
+	return &pb.NamespaceExistsResponse{
+		Exists: s.namespaces[req.NamespaceName],
+	}, nil
 }
 
-func (s *mockServer) AlterUser(ctx context.Context, req *pb.AlterUserRequest) (*pb.AlterUserResponse, error) {
-	return &pb.AlterUserResponse{}, nil
-}
+// CreateTable implements the CreateTable RPC.
+func (s *Server) CreateTable(ctx context.Context, req *pb.CreateTableRequest) (*pb.CreateTableResponse, error) {
+	s.logf("CreateTable: %v", req)
+	if err := requireName("table", req.TableName); err != nil {
+		return nil, err
+	}
+	if err := validateTableMetadata(req.TableMetadata); err != nil {
+		return nil, err
+	}
 
-func (s *mockServer) DropUser(ctx context.Context, req *pb.DropUserRequest) (*pb.DropUserResponse, error) {
-	return &pb.DropUserResponse{}, nil
-}
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) Grant(ctx context.Context, req *pb.GrantRequest) (*pb.GrantResponse, error) {
-	return &pb.GrantResponse{}, nil
+	if err := s.namespaceNotFound(req.NamespaceName); err != nil {
+		return nil, err
+	}
+	if _, exists := s.tables[req.NamespaceName][req.TableName]; exists {
+		if req.IfNotExists {
+			return &pb.CreateTableResponse{}, nil
+		}
+		return nil, status.Errorf(codes.AlreadyExists, "table %s.%s already exists", req.NamespaceName, req.TableName)
+	}
+	s.tables[req.NamespaceName][req.TableName] = clone(req.TableMetadata)
+	delete(s.records, tableName{req.NamespaceName, req.TableName})
+	return &pb.CreateTableResponse{}, nil
 }
 
-func (s *mockServer) Revoke(ctx context.Context, req *pb.RevokeRequest) (*pb.RevokeResponse, error) {
-	return &pb.RevokeResponse{}, nil
+// DropTable implements the DropTable RPC.
+func (s *Server) DropTable(ctx context.Context, req *pb.DropTableRequest) (*pb.DropTableResponse, error) {
+	s.logf("DropTable: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, err := s.lookupTable(req.NamespaceName, req.TableName); err != nil {
+		if req.IfExists && status.Code(err) == codes.NotFound {
+			return &pb.DropTableResponse{}, nil
+		}
+		return nil, err
+	}
+	delete(s.tables[req.NamespaceName], req.TableName)
+	delete(s.records, tableName{req.NamespaceName, req.TableName})
+	s.dropPrivileges(func(target privilegeTarget) bool {
+		return target.namespace == req.NamespaceName && target.table == req.TableName
+	})
+	return &pb.DropTableResponse{}, nil
 }
 
-func (s *mockServer) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
-	return &pb.GetUserResponse{}, nil
-}
+// TableExists implements the TableExists RPC.
+func (s *Server) TableExists(ctx context.Context, req *pb.TableExistsRequest) (*pb.TableExistsResponse, error) {
+	s.logf("TableExists: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) GetUsers(ctx context.Context, req *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
-	return &pb.GetUsersResponse{}, nil
+	_, exists := s.tables[req.NamespaceName][req.TableName]
+	return &pb.TableExistsResponse{
+		Exists: exists,
+	}, nil
 }
 
-func (s *mockServer) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.GetCurrentUserResponse, error) {
-	return &pb.GetCurrentUserResponse{}, nil
-}
+// GetTableMetadata implements the GetTableMetadata RPC.
+func (s *Server) GetTableMetadata(ctx context.Context, req *pb.GetTableMetadataRequest) (*pb.GetTableMetadataResponse, error) {
+	s.logf("GetTableMetadata: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) GetPrivileges(ctx context.Context, req *pb.GetPrivilegesRequest) (*pb.GetPrivilegesResponse, error) {
-	return &pb.GetPrivilegesResponse{}, nil
+	metadata, err := s.lookupTable(req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetTableMetadataResponse{
+		TableMetadata: clone(metadata),
+	}, nil
 }
 
-func (s *mockServer) CreatePolicy(ctx context.Context, req *pb.CreatePolicyRequest) (*pb.CreatePolicyResponse, error) {
-	return &pb.CreatePolicyResponse{}, nil
-}
+// TruncateTable implements the TruncateTable RPC. Like in ScalarDB, truncation is not
+// transactional, so open transactions no longer see the records either.
+func (s *Server) TruncateTable(ctx context.Context, req *pb.TruncateTableRequest) (*pb.TruncateTableResponse, error) {
+	s.logf("TruncateTable: %v", req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-func (s *mockServer) EnablePolicy(ctx context.Context, req *pb.EnablePolicyRequest) (*pb.EnablePolicyResponse, error) {
-	return &pb.EnablePolicyResponse{}, nil
+	if _, err := s.lookupTable(req.NamespaceName, req.TableName); err != nil {
+		return nil, err
+	}
+	delete(s.records, tableName{req.NamespaceName, req.TableName})
+	return &pb.TruncateTableResponse{}, nil
 }
 
-func (s *mockServer) DisablePolicy(ctx context.Context, req *pb.DisablePolicyRequest) (*pb.DisablePolicyResponse, error) {
-	return &pb.DisablePolicyResponse{}, nil
-}
+// CreateIndex implements the CreateIndex RPC.
+func (s *Server) CreateIndex(ctx context.Context, req *pb.CreateIndexRequest) (*pb.CreateIndexResponse, error) {
+	s.logf("CreateIndex: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) GetPolicy(ctx context.Context, req *pb.GetPolicyRequest) (*pb.GetPolicyResponse, error) {
-	return &pb.GetPolicyResponse{}, nil
+	metadata, err := s.lookupTable(req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := metadata.Columns[req.ColumnName]; !ok {
+		return nil, status.Errorf(codes.InvalidArgument, "column %s does not exist in table %s.%s", req.ColumnName, req.NamespaceName, req.TableName)
+	}
+	if slices.Contains(metadata.SecondaryIndexColumnNames, req.ColumnName) {
+		if req.IfNotExists {
+			return &pb.CreateIndexResponse{}, nil
+		}
+		return nil, status.Errorf(codes.AlreadyExists, "index on column %s of table %s.%s already exists", req.ColumnName, req.NamespaceName, req.TableName)
+	}
+	metadata.SecondaryIndexColumnNames = addName(metadata.SecondaryIndexColumnNames, req.ColumnName)
+	return &pb.CreateIndexResponse{}, nil
 }
 
-func (s *mockServer) GetPolicies(ctx context.Context, req *pb.GetPoliciesRequest) (*pb.GetPoliciesResponse, error) {
-	return &pb.GetPoliciesResponse{}, nil
-}
+// DropIndex implements the DropIndex RPC.
+func (s *Server) DropIndex(ctx context.Context, req *pb.DropIndexRequest) (*pb.DropIndexResponse, error) {
+	s.logf("DropIndex: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) CreateLevel(ctx context.Context, req *pb.CreateLevelRequest) (*pb.CreateLevelResponse, error) {
-	return &pb.CreateLevelResponse{}, nil
+	metadata, err := s.lookupTable(req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(metadata.SecondaryIndexColumnNames, req.ColumnName) {
+		if req.IfExists {
+			return &pb.DropIndexResponse{}, nil
+		}
+		return nil, status.Errorf(codes.NotFound, "index on column %s of table %s.%s does not exist", req.ColumnName, req.NamespaceName, req.TableName)
+	}
+	metadata.SecondaryIndexColumnNames = removeName(metadata.SecondaryIndexColumnNames, req.ColumnName)
+	return &pb.DropIndexResponse{}, nil
 }
 
-func (s *mockServer) DropLevel(ctx context.Context, req *pb.DropLevelRequest) (*pb.DropLevelResponse, error) {
-	return &pb.DropLevelResponse{}, nil
-}
+// IndexExists implements the IndexExists RPC.
+func (s *Server) IndexExists(ctx context.Context, req *pb.IndexExistsRequest) (*pb.IndexExistsResponse, error) {
+	s.logf("IndexExists: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) GetLevel(ctx context.Context, req *pb.GetLevelRequest) (*pb.GetLevelResponse, error) {
-	return &pb.GetLevelResponse{}, nil
+	metadata, err := s.lookupTable(req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.IndexExistsResponse{
+		Exists: slices.Contains(metadata.SecondaryIndexColumnNames, req.ColumnName),
+	}, nil
 }
 
-func (s *mockServer) GetLevels(ctx context.Context, req *pb.GetLevelsRequest) (*pb.GetLevelsResponse, error) {
-	return &pb.GetLevelsResponse{}, nil
-}
+// RepairNamespace implements the RepairNamespace RPC.
+func (s *Server) RepairNamespace(ctx context.Context, req *pb.RepairNamespaceRequest) (*pb.RepairNamespaceResponse, error) {
+	s.logf("RepairNamespace: %v", req)
+	if err := requireName("namespace", req.NamespaceName); err != nil {
+		return nil, err
+	}
 
-func (s *mockServer) CreateCompartment(ctx context.Context, req *pb.CreateCompartmentRequest) (*pb.CreateCompartmentResponse, error) {
-	return &pb.CreateCompartmentResponse{}, nil
-}
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) DropCompartment(ctx context.Context, req *pb.DropCompartmentRequest) (*pb.DropCompartmentResponse, error) {
-	return &pb.DropCompartmentResponse{}, nil
+	s.namespaces[req.NamespaceName] = true
+	delete(s.unlisted, req.NamespaceName)
+	if _, exists := s.tables[req.NamespaceName]; !exists {
+		s.tables[req.NamespaceName] = make(map[string]*pb.TableMetadata)
+	}
+	return &pb.RepairNamespaceResponse{}, nil
 }
 
-func (s *mockServer) GetCompartment(ctx context.Context, req *pb.GetCompartmentRequest) (*pb.GetCompartmentResponse, error) {
-	return &pb.GetCompartmentResponse{}, nil
-}
+// RepairTable implements the RepairTable RPC.
+func (s *Server) RepairTable(ctx context.Context, req *pb.RepairTableRequest) (*pb.RepairTableResponse, error) {
+	s.logf("RepairTable: %v", req)
+	if err := requireName("table", req.TableName); err != nil {
+		return nil, err
+	}
+	if err := validateTableMetadata(req.TableMetadata); err != nil {
+		return nil, err
+	}
 
-func (s *mockServer) GetCompartments(ctx context.Context, req *pb.GetCompartmentsRequest) (*pb.GetCompartmentsResponse, error) {
-	return &pb.GetCompartmentsResponse{}, nil
-}
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) CreateGroup(ctx context.Context, req *pb.CreateGroupRequest) (*pb.CreateGroupResponse, error) {
-	return &pb.CreateGroupResponse{}, nil
+	if err := s.namespaceNotFound(req.NamespaceName); err != nil {
+		return nil, err
+	}
+	s.tables[req.NamespaceName][req.TableName] = clone(req.TableMetadata)
+	return &pb.RepairTableResponse{}, nil
 }
 
-func (s *mockServer) DropGroup(ctx context.Context, req *pb.DropGroupRequest) (*pb.DropGroupResponse, error) {
-	return &pb.DropGroupResponse{}, nil
-}
+// AddNewColumnToTable implements the AddNewColumnToTable RPC.
+func (s *Server) AddNewColumnToTable(ctx context.Context, req *pb.AddNewColumnToTableRequest) (*pb.AddNewColumnToTableResponse, error) {
+	s.logf("AddNewColumnToTable: %v", req)
+	if err := requireName("column", req.ColumnName); err != nil {
+		return nil, err
+	}
+	if req.ColumnDataType == pb.DataType_DATA_TYPE_UNSPECIFIED {
+		return nil, status.Errorf(codes.InvalidArgument, "the data type of column %s is not specified", req.ColumnName)
+	}
 
-func (s *mockServer) GetGroup(ctx context.Context, req *pb.GetGroupRequest) (*pb.GetGroupResponse, error) {
-	return &pb.GetGroupResponse{}, nil
-}
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) GetGroups(ctx context.Context, req *pb.GetGroupsRequest) (*pb.GetGroupsResponse, error) {
-	return &pb.GetGroupsResponse{}, nil
+	metadata, err := s.lookupTable(req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	if _, exists := metadata.Columns[req.ColumnName]; exists {
+		return nil, status.Errorf(codes.InvalidArgument, "column %s already exists in table %s.%s", req.ColumnName, req.NamespaceName, req.TableName)
+	}
+	metadata.Columns[req.ColumnName] = req.ColumnDataType
+	if req.Encrypted {
+		metadata.EncryptedColumns = addName(metadata.EncryptedColumns, req.ColumnName)
+	}
+	return &pb.AddNewColumnToTableResponse{}, nil
 }
 
-func (s *mockServer) SetLevelsToUser(ctx context.Context, req *pb.SetLevelsToUserRequest) (*pb.SetLevelsToUserResponse, error) {
-	return &pb.SetLevelsToUserResponse{}, nil
-}
+// CreateCoordinatorTables implements the CreateCoordinatorTables RPC.
+func (s *Server) CreateCoordinatorTables(ctx context.Context, req *pb.CreateCoordinatorTablesRequest) (*pb.CreateCoordinatorTablesResponse, error) {
+	s.logf("CreateCoordinatorTables: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) AddCompartmentToUser(ctx context.Context, req *pb.AddCompartmentToUserRequest) (*pb.AddCompartmentToUserResponse, error) {
-	return &pb.AddCompartmentToUserResponse{}, nil
+	if s.coordinatorTables && !req.IfNotExist {
+		return nil, status.Error(codes.AlreadyExists, "coordinator tables already exist")
+	}
+	s.coordinatorTables = true
+	return &pb.CreateCoordinatorTablesResponse{}, nil
 }
 
-func (s *mockServer) RemoveCompartmentFromUser(ctx context.Context, req *pb.RemoveCompartmentFromUserRequest) (*pb.RemoveCompartmentFromUserResponse, error) {
-	return &pb.RemoveCompartmentFromUserResponse{}, nil
-}
+// DropCoordinatorTables implements the DropCoordinatorTables RPC.
+func (s *Server) DropCoordinatorTables(ctx context.Context, req *pb.DropCoordinatorTablesRequest) (*pb.DropCoordinatorTablesResponse, error) {
+	s.logf("DropCoordinatorTables: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) AddGroupToUser(ctx context.Context, req *pb.AddGroupToUserRequest) (*pb.AddGroupToUserResponse, error) {
-	return &pb.AddGroupToUserResponse{}, nil
+	if !s.coordinatorTables && !req.IfExist {
+		return nil, status.Error(codes.NotFound, "coordinator tables do not exist")
+	}
+	s.coordinatorTables = false
+	return &pb.DropCoordinatorTablesResponse{}, nil
 }
 
-func (s *mockServer) RemoveGroupFromUser(ctx context.Context, req *pb.RemoveGroupFromUserRequest) (*pb.RemoveGroupFromUserResponse, error) {
-	return &pb.RemoveGroupFromUserResponse{}, nil
-}
+// TruncateCoordinatorTables implements the TruncateCoordinatorTables RPC.
+func (s *Server) TruncateCoordinatorTables(ctx context.Context, req *pb.TruncateCoordinatorTablesRequest) (*pb.TruncateCoordinatorTablesResponse, error) {
+	s.logf("TruncateCoordinatorTables: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) DropUserTagInfoFromUser(ctx context.Context, req *pb.DropUserTagInfoFromUserRequest) (*pb.DropUserTagInfoFromUserResponse, error) {
-	return &pb.DropUserTagInfoFromUserResponse{}, nil
+	if !s.coordinatorTables {
+		return nil, status.Error(codes.NotFound, "coordinator tables do not exist")
+	}
+	return &pb.TruncateCoordinatorTablesResponse{}, nil
 }
 
-func (s *mockServer) GetUserTagInfo(ctx context.Context, req *pb.GetUserTagInfoRequest) (*pb.GetUserTagInfoResponse, error) {
-	return &pb.GetUserTagInfoResponse{}, nil
-}
+// CoordinatorTablesExist implements the CoordinatorTablesExist RPC.
+func (s *Server) CoordinatorTablesExist(ctx context.Context, req *pb.CoordinatorTablesExistRequest) (*pb.CoordinatorTablesExistResponse, error) {
+	s.logf("CoordinatorTablesExist: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) CreateNamespacePolicy(ctx context.Context, req *pb.CreateNamespacePolicyRequest) (*pb.CreateNamespacePolicyResponse, error) {
-	return &pb.CreateNamespacePolicyResponse{}, nil
+	return &pb.CoordinatorTablesExistResponse{Exist: s.coordinatorTables}, nil
 }
 
-func (s *mockServer) EnableNamespacePolicy(ctx context.Context, req *pb.EnableNamespacePolicyRequest) (*pb.EnableNamespacePolicyResponse, error) {
-	return &pb.EnableNamespacePolicyResponse{}, nil
-}
+// RepairCoordinatorTables implements the RepairCoordinatorTables RPC.
+func (s *Server) RepairCoordinatorTables(ctx context.Context, req *pb.RepairCoordinatorTablesRequest) (*pb.RepairCoordinatorTablesResponse, error) {
+	s.logf("RepairCoordinatorTables: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
 
-func (s *mockServer) DisableNamespacePolicy(ctx context.Context, req *pb.DisableNamespacePolicyRequest) (*pb.DisableNamespacePolicyResponse, error) {
-	return &pb.DisableNamespacePolicyResponse{}, nil
+	s.coordinatorTables = true
+	return &pb.RepairCoordinatorTablesResponse{}, nil
 }
 
-func (s *mockServer) GetNamespacePolicy(ctx context.Context, req *pb.GetNamespacePolicyRequest) (*pb.GetNamespacePolicyResponse, error) {
-	return &pb.GetNamespacePolicyResponse{}, nil
-}
+// GetNamespaceNames implements the GetNamespaceNames RPC.
+func (s *Server) GetNamespaceNames(ctx context.Context, req *pb.GetNamespaceNamesRequest) (*pb.GetNamespaceNamesResponse, error) {
+	s.logf("GetNamespaceNames: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) GetNamespacePolicies(ctx context.Context, req *pb.GetNamespacePoliciesRequest) (*pb.GetNamespacePoliciesResponse, error) {
-	return &pb.GetNamespacePoliciesResponse{}, nil
+	var names []string
+	for _, name := range sortedKeys(s.namespaces) {
+		if !s.unlisted[name] {
+			names = append(names, name)
+		}
+	}
+	return &pb.GetNamespaceNamesResponse{NamespaceNames: names}, nil
 }
 
-func (s *mockServer) CreateTablePolicy(ctx context.Context, req *pb.CreateTablePolicyRequest) (*pb.CreateTablePolicyResponse, error) {
-	return &pb.CreateTablePolicyResponse{}, nil
-}
+// GetNamespaceTableNames implements the GetNamespaceTableNames RPC.
+func (s *Server) GetNamespaceTableNames(ctx context.Context, req *pb.GetNamespaceTableNamesRequest) (*pb.GetNamespaceTableNamesResponse, error) {
+	s.logf("GetNamespaceTableNames: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
 
-func (s *mockServer) EnableTablePolicy(ctx context.Context, req *pb.EnableTablePolicyRequest) (*pb.EnableTablePolicyResponse, error) {
-	return &pb.EnableTablePolicyResponse{}, nil
+	if err := s.namespaceNotFound(req.NamespaceName); err != nil {
+		return nil, err
+	}
+	return &pb.GetNamespaceTableNamesResponse{TableNames: sortedKeys(s.tables[req.NamespaceName])}, nil
 }
 
-func (s *mockServer) DisableTablePolicy(ctx context.Context, req *pb.DisableTablePolicyRequest) (*pb.DisableTablePolicyResponse, error) {
-	return &pb.DisableTablePolicyResponse{}, nil
+// ImportTable implements the ImportTable RPC. The mock has no underlying storage to
+// import from.
+func (s *Server) ImportTable(ctx context.Context, req *pb.ImportTableRequest) (*pb.ImportTableResponse, error) {
+	s.logf("ImportTable: %v", req)
+	return nil, status.Error(codes.Unimplemented, "the mock server has no storage to import tables from")
 }
 
-func (s *mockServer) GetTablePolicy(ctx context.Context, req *pb.GetTablePolicyRequest) (*pb.GetTablePolicyResponse, error) {
-	return &pb.GetTablePolicyResponse{}, nil
+// Upgrade implements the Upgrade RPC.
+func (s *Server) Upgrade(ctx context.Context, req *pb.UpgradeRequest) (*pb.UpgradeResponse, error) {
+	s.logf("Upgrade: %v", req)
+	return &pb.UpgradeResponse{}, nil
 }
 
-func (s *mockServer) GetTablePolicies(ctx context.Context, req *pb.GetTablePoliciesRequest) (*pb.GetTablePoliciesResponse, error) {
-	return &pb.GetTablePoliciesResponse{}, nil
+// sortedKeys returns the keys of a map in sorted order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
 }
 
-func main() {
-	flag.Parse()
-
-	// Check if port is specified via environment variable
-	if portEnv := os.Getenv("SCALARDB_MOCK_PORT"); portEnv != "" {
-		if p, err := strconv.Atoi(portEnv); err == nil {
-			*port = p
-		}
+// addName adds a name to a list of names if it is not there yet, keeping it sorted.
+func addName(names []string, name string) []string {
+	if slices.Contains(names, name) {
+		return names
 	}
+	names = append(names, name)
+	sort.Strings(names)
+	return names
+}
 
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%d", *port))
-	if err != nil {
-		log.Fatalf("failed to listen: %v", err)
-	}
-	s := grpc.NewServer()
-	pb.RegisterDistributedTransactionAdminServer(s, newMockServer())
-	// Register reflection service on gRPC server.
-	reflection.Register(s)
-	log.Printf("Mock ScalarDB Cluster server listening at %v", lis.Addr())
-	if err := s.Serve(lis); err != nil {
-		log.Fatalf("failed to serve: %v", err)
-	}
+// removeName removes a name from a list of names.
+func removeName(names []string, name string) []string {
+	return slices.DeleteFunc(names, func(n string) bool { return n == name })
 }
@@ -0,0 +1,207 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"testing"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// testClient starts a server on bufconn and returns a client connected to it.
+func testClient(t *testing.T) pb.DistributedTransactionAdminClient {
+	t.Helper()
+	server := ListenBufconn(WithLogger(nil))
+	t.Cleanup(server.Stop)
+
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return pb.NewDistributedTransactionAdminClient(conn)
+}
+
+func usersMetadata() *pb.TableMetadata {
+	return &pb.TableMetadata{
+		Columns: map[string]pb.DataType{
+			"id":    pb.DataType_DATA_TYPE_INT,
+			"email": pb.DataType_DATA_TYPE_TEXT,
+		},
+		PartitionKeyColumnNames: []string{"id"},
+	}
+}
+
+func TestStatusCodes(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: "users", TableMetadata: usersMetadata()}); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name string
+		call func() error
+		want codes.Code
+	}{
+		{
+			name: "namespace already exists",
+			call: func() error {
+				_, err := client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"})
+				return err
+			},
+			want: codes.AlreadyExists,
+		},
+		{
+			name: "namespace is not empty",
+			call: func() error {
+				_, err := client.DropNamespace(ctx, &pb.DropNamespaceRequest{NamespaceName: "ns"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "table does not exist",
+			call: func() error {
+				_, err := client.GetTableMetadata(ctx, &pb.GetTableMetadataRequest{NamespaceName: "ns", TableName: "orders"})
+				return err
+			},
+			want: codes.NotFound,
+		},
+		{
+			name: "partition key is not a column",
+			call: func() error {
+				metadata := usersMetadata()
+				metadata.PartitionKeyColumnNames = []string{"user_id"}
+				_, err := client.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: "orders", TableMetadata: metadata})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "index on unknown column",
+			call: func() error {
+				_, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{NamespaceName: "ns", TableName: "users", ColumnName: "name"})
+				return err
+			},
+			want: codes.InvalidArgument,
+		},
+		{
+			name: "grant to unknown user",
+			call: func() error {
+				_, err := client.Grant(ctx, &pb.GrantRequest{Username: "alice", NamespaceName: "ns", Privileges: []pb.Privilege{pb.Privilege_PRIVILEGE_READ}})
+				return err
+			},
+			want: codes.NotFound,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := status.Code(tt.call()); got != tt.want {
+				t.Errorf("got %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConcurrentTableChanges(t *testing.T) {
+	client := testClient(t)
+	ctx := context.Background()
+
+	if _, err := client.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 16)
+	for i := range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			table := fmt.Sprintf("t%d", i)
+			if _, err := client.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: table, TableMetadata: usersMetadata()}); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := client.AddNewColumnToTable(ctx, &pb.AddNewColumnToTableRequest{
+				NamespaceName: "ns", TableName: table, ColumnName: "age", ColumnDataType: pb.DataType_DATA_TYPE_INT,
+			}); err != nil {
+				errs <- err
+				return
+			}
+			if _, err := client.CreateIndex(ctx, &pb.CreateIndexRequest{NamespaceName: "ns", TableName: table, ColumnName: "email"}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+
+	names, err := client.GetNamespaceTableNames(ctx, &pb.GetNamespaceTableNamesRequest{NamespaceName: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(names.TableNames) != 16 {
+		t.Fatalf("got %d tables, want 16", len(names.TableNames))
+	}
+	for _, table := range names.TableNames {
+		resp, err := client.GetTableMetadata(ctx, &pb.GetTableMetadataRequest{NamespaceName: "ns", TableName: table})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if resp.TableMetadata.Columns["age"] != pb.DataType_DATA_TYPE_INT {
+			t.Errorf("%s: column age was not added", table)
+		}
+		if !slices.Equal(resp.TableMetadata.SecondaryIndexColumnNames, []string{"email"}) {
+			t.Errorf("%s: got secondary indexes %v", table, resp.TableMetadata.SecondaryIndexColumnNames)
+		}
+	}
+}
+
+func TestUnlistNamespace(t *testing.T) {
+	server := ListenBufconn(WithLogger(nil))
+	t.Cleanup(server.Stop)
+	ctx := context.Background()
+
+	if _, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	server.UnlistNamespace("ns")
+
+	exists, err := server.NamespaceExists(ctx, &pb.NamespaceExistsRequest{NamespaceName: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists.Exists {
+		t.Error("an unlisted namespace should still exist")
+	}
+	names, err := server.GetNamespaceNames(ctx, &pb.GetNamespaceNamesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if slices.Contains(names.NamespaceNames, "ns") {
+		t.Errorf("namespace names = %v, want ns unlisted", names.NamespaceNames)
+	}
+
+	if _, err := server.RepairNamespace(ctx, &pb.RepairNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	names, err = server.GetNamespaceNames(ctx, &pb.GetNamespaceNamesRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Contains(names.NamespaceNames, "ns") {
+		t.Errorf("namespace names = %v, want ns listed after repair", names.NamespaceNames)
+	}
+}
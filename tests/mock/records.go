@@ -0,0 +1,445 @@
+package mock
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// tableName identifies a table.
+type tableName struct {
+	namespace string
+	table     string
+}
+
+func (t tableName) String() string {
+	return t.namespace + "." + t.table
+}
+
+// record is the columns of a record by name, including its key columns. Columns that
+// have never been written are absent and read as null.
+type record map[string]*pb.Column
+
+// recordVersion is a committed version of a record. A nil record marks a deletion.
+type recordVersion struct {
+	commitTS uint64
+	record   record
+}
+
+// columnValue returns the data type and the value of a column. The value is nil for null,
+// and otherwise a bool, int64, float64, string or []byte.
+func columnValue(c *pb.Column) (pb.DataType, any) {
+	switch v := c.GetValue().(type) {
+	case *pb.Column_BooleanValue_:
+		if v.BooleanValue.Value == nil {
+			return pb.DataType_DATA_TYPE_BOOLEAN, nil
+		}
+		return pb.DataType_DATA_TYPE_BOOLEAN, *v.BooleanValue.Value
+	case *pb.Column_IntValue_:
+		if v.IntValue.Value == nil {
+			return pb.DataType_DATA_TYPE_INT, nil
+		}
+		return pb.DataType_DATA_TYPE_INT, int64(*v.IntValue.Value)
+	case *pb.Column_BigintValue:
+		if v.BigintValue.Value == nil {
+			return pb.DataType_DATA_TYPE_BIGINT, nil
+		}
+		return pb.DataType_DATA_TYPE_BIGINT, *v.BigintValue.Value
+	case *pb.Column_FloatValue_:
+		if v.FloatValue.Value == nil {
+			return pb.DataType_DATA_TYPE_FLOAT, nil
+		}
+		return pb.DataType_DATA_TYPE_FLOAT, float64(*v.FloatValue.Value)
+	case *pb.Column_DoubleValue_:
+		if v.DoubleValue.Value == nil {
+			return pb.DataType_DATA_TYPE_DOUBLE, nil
+		}
+		return pb.DataType_DATA_TYPE_DOUBLE, *v.DoubleValue.Value
+	case *pb.Column_TextValue_:
+		if v.TextValue.Value == nil {
+			return pb.DataType_DATA_TYPE_TEXT, nil
+		}
+		return pb.DataType_DATA_TYPE_TEXT, *v.TextValue.Value
+	case *pb.Column_BlobValue_:
+		if v.BlobValue.Value == nil {
+			return pb.DataType_DATA_TYPE_BLOB, nil
+		}
+		return pb.DataType_DATA_TYPE_BLOB, v.BlobValue.Value
+	case *pb.Column_DateValue_:
+		if v.DateValue.Value == nil {
+			return pb.DataType_DATA_TYPE_DATE, nil
+		}
+		return pb.DataType_DATA_TYPE_DATE, int64(*v.DateValue.Value)
+	case *pb.Column_TimeValue_:
+		if v.TimeValue.Value == nil {
+			return pb.DataType_DATA_TYPE_TIME, nil
+		}
+		return pb.DataType_DATA_TYPE_TIME, *v.TimeValue.Value
+	case *pb.Column_TimestampValue_:
+		if v.TimestampValue.Value == nil {
+			return pb.DataType_DATA_TYPE_TIMESTAMP, nil
+		}
+		return pb.DataType_DATA_TYPE_TIMESTAMP, *v.TimestampValue.Value
+	case *pb.Column_TimestamptzValue:
+		if v.TimestamptzValue.Value == nil {
+			return pb.DataType_DATA_TYPE_TIMESTAMPTZ, nil
+		}
+		return pb.DataType_DATA_TYPE_TIMESTAMPTZ, *v.TimestamptzValue.Value
+	}
+	return pb.DataType_DATA_TYPE_UNSPECIFIED, nil
+}
+
+// nullColumn returns a null column of a data type.
+func nullColumn(name string, dataType pb.DataType) *pb.Column {
+	c := &pb.Column{Name: name}
+	switch dataType {
+	case pb.DataType_DATA_TYPE_BOOLEAN:
+		c.Value = &pb.Column_BooleanValue_{BooleanValue: &pb.Column_BooleanValue{}}
+	case pb.DataType_DATA_TYPE_INT:
+		c.Value = &pb.Column_IntValue_{IntValue: &pb.Column_IntValue{}}
+	case pb.DataType_DATA_TYPE_BIGINT:
+		c.Value = &pb.Column_BigintValue{BigintValue: &pb.Column_BigIntValue{}}
+	case pb.DataType_DATA_TYPE_FLOAT:
+		c.Value = &pb.Column_FloatValue_{FloatValue: &pb.Column_FloatValue{}}
+	case pb.DataType_DATA_TYPE_DOUBLE:
+		c.Value = &pb.Column_DoubleValue_{DoubleValue: &pb.Column_DoubleValue{}}
+	case pb.DataType_DATA_TYPE_TEXT:
+		c.Value = &pb.Column_TextValue_{TextValue: &pb.Column_TextValue{}}
+	case pb.DataType_DATA_TYPE_BLOB:
+		c.Value = &pb.Column_BlobValue_{BlobValue: &pb.Column_BlobValue{}}
+	case pb.DataType_DATA_TYPE_DATE:
+		c.Value = &pb.Column_DateValue_{DateValue: &pb.Column_DateValue{}}
+	case pb.DataType_DATA_TYPE_TIME:
+		c.Value = &pb.Column_TimeValue_{TimeValue: &pb.Column_TimeValue{}}
+	case pb.DataType_DATA_TYPE_TIMESTAMP:
+		c.Value = &pb.Column_TimestampValue_{TimestampValue: &pb.Column_TimestampValue{}}
+	case pb.DataType_DATA_TYPE_TIMESTAMPTZ:
+		c.Value = &pb.Column_TimestamptzValue{TimestamptzValue: &pb.Column_TimestampTZValue{}}
+	}
+	return c
+}
+
+// compareValues compares two non-null values of the same data type.
+func compareValues(a, b any) int {
+	switch a := a.(type) {
+	case bool:
+		b := b.(bool)
+		switch {
+		case a == b:
+			return 0
+		case !a:
+			return -1
+		default:
+			return 1
+		}
+	case int64:
+		return cmp.Compare(a, b.(int64))
+	case float64:
+		return cmp.Compare(a, b.(float64))
+	case string:
+		return strings.Compare(a, b.(string))
+	case []byte:
+		return bytes.Compare(a, b.([]byte))
+	}
+	return 0
+}
+
+// compareColumns compares two columns of the same data type. Null sorts first.
+func compareColumns(a, b *pb.Column) int {
+	_, av := columnValue(a)
+	_, bv := columnValue(b)
+	switch {
+	case av == nil && bv == nil:
+		return 0
+	case av == nil:
+		return -1
+	case bv == nil:
+		return 1
+	}
+	return compareValues(av, bv)
+}
+
+// checkColumn returns InvalidArgument unless a column is defined in the table with the
+// data type of its value.
+func checkColumn(metadata *pb.TableMetadata, c *pb.Column) error {
+	dataType, ok := metadata.Columns[c.GetName()]
+	if !ok {
+		return status.Errorf(codes.InvalidArgument, "column %s is not defined", c.GetName())
+	}
+	if got, _ := columnValue(c); got != dataType {
+		return status.Errorf(codes.InvalidArgument, "column %s has type %s, but a %s value was given", c.GetName(), dataType, got)
+	}
+	return nil
+}
+
+// keyRecord checks that a key has exactly the given key columns with their data types and
+// returns them as a record.
+func keyRecord(metadata *pb.TableMetadata, key *pb.Key, names []string, kind string) (record, error) {
+	rec := make(record)
+	for _, c := range key.GetColumns() {
+		if err := checkColumn(metadata, c); err != nil {
+			return nil, err
+		}
+		if !slices.Contains(names, c.Name) {
+			return nil, status.Errorf(codes.InvalidArgument, "column %s is not a %s column", c.Name, kind)
+		}
+		if _, v := columnValue(c); v == nil {
+			return nil, status.Errorf(codes.InvalidArgument, "%s column %s must not be null", kind, c.Name)
+		}
+		rec[c.Name] = clone(c)
+	}
+	if len(rec) != len(names) {
+		return nil, status.Errorf(codes.InvalidArgument, "the %s must have the columns %v", kind, names)
+	}
+	return rec, nil
+}
+
+// primaryKey checks the partition and clustering keys of an operation and returns the
+// key columns as a record together with the encoded primary key.
+func primaryKey(metadata *pb.TableMetadata, partition, clustering *pb.Key) (record, string, error) {
+	rec, err := keyRecord(metadata, partition, metadata.PartitionKeyColumnNames, "partition key")
+	if err != nil {
+		return nil, "", err
+	}
+	clusteringRec, err := keyRecord(metadata, clustering, metadata.ClusteringKeyColumnNames, "clustering key")
+	if err != nil {
+		return nil, "", err
+	}
+	for name, c := range clusteringRec {
+		rec[name] = c
+	}
+	return rec, encodeKey(metadata, rec), nil
+}
+
+// encodeKey encodes the primary key of a record as a string.
+func encodeKey(metadata *pb.TableMetadata, rec record) string {
+	var b strings.Builder
+	for _, names := range [][]string{metadata.PartitionKeyColumnNames, metadata.ClusteringKeyColumnNames} {
+		for _, name := range names {
+			data, _ := proto.MarshalOptions{Deterministic: true}.Marshal(rec[name])
+			fmt.Fprintf(&b, "%d:%s", len(data), data)
+		}
+	}
+	return b.String()
+}
+
+// matchesPartition reports whether a record is in the partition of a key record.
+func matchesPartition(metadata *pb.TableMetadata, rec, partition record) bool {
+	for _, name := range metadata.PartitionKeyColumnNames {
+		if compareColumns(rec[name], partition[name]) != 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// compareClusteringPrefix compares the clustering key of a record with a key that has a
+// prefix of the clustering key columns.
+func compareClusteringPrefix(metadata *pb.TableMetadata, rec record, key *pb.Key) int {
+	for i, c := range key.GetColumns() {
+		if c := compareColumns(rec[metadata.ClusteringKeyColumnNames[i]], c); c != 0 {
+			return c
+		}
+	}
+	return 0
+}
+
+// checkClusteringPrefix returns InvalidArgument unless a key has a prefix of the
+// clustering key columns in order.
+func checkClusteringPrefix(metadata *pb.TableMetadata, key *pb.Key) error {
+	if len(key.GetColumns()) > len(metadata.ClusteringKeyColumnNames) {
+		return status.Errorf(codes.InvalidArgument, "the clustering key has more columns than %v", metadata.ClusteringKeyColumnNames)
+	}
+	for i, c := range key.GetColumns() {
+		if err := checkColumn(metadata, c); err != nil {
+			return err
+		}
+		if c.Name != metadata.ClusteringKeyColumnNames[i] {
+			return status.Errorf(codes.InvalidArgument, "the clustering key must be a prefix of %v", metadata.ClusteringKeyColumnNames)
+		}
+	}
+	return nil
+}
+
+// checkExpressions returns InvalidArgument unless the expressions refer to columns of the
+// table with values of their data types.
+func checkExpressions(metadata *pb.TableMetadata, expressions []*pb.ConditionalExpression) error {
+	for _, expr := range expressions {
+		switch expr.Operator {
+		case pb.ConditionalExpression_OPERATOR_IS_NULL, pb.ConditionalExpression_OPERATOR_IS_NOT_NULL:
+			if _, ok := metadata.Columns[expr.GetColumn().GetName()]; !ok {
+				return status.Errorf(codes.InvalidArgument, "column %s is not defined", expr.GetColumn().GetName())
+			}
+			continue
+		case pb.ConditionalExpression_OPERATOR_UNSPECIFIED:
+			return status.Error(codes.InvalidArgument, "the operator of a conditional expression is not specified")
+		case pb.ConditionalExpression_OPERATOR_LIKE, pb.ConditionalExpression_OPERATOR_NOT_LIKE:
+			if metadata.Columns[expr.GetColumn().GetName()] != pb.DataType_DATA_TYPE_TEXT {
+				return status.Errorf(codes.InvalidArgument, "LIKE is only supported on TEXT columns, but %s is not", expr.GetColumn().GetName())
+			}
+		}
+		if err := checkColumn(metadata, expr.GetColumn()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// matchesExpressions reports whether a record satisfies all of the expressions.
+func matchesExpressions(rec record, expressions []*pb.ConditionalExpression) bool {
+	for _, expr := range expressions {
+		if !matchesExpression(rec, expr) {
+			return false
+		}
+	}
+	return true
+}
+
+// matchesConjunctions reports whether a record satisfies any of the conjunctions. A record
+// always matches if there are none.
+func matchesConjunctions(rec record, conjunctions []*pb.Conjunction) bool {
+	if len(conjunctions) == 0 {
+		return true
+	}
+	for _, conjunction := range conjunctions {
+		if matchesExpressions(rec, conjunction.ConditionalExpressions) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesExpression reports whether a record satisfies an expression. Comparisons with
+// null are false, as in SQL.
+func matchesExpression(rec record, expr *pb.ConditionalExpression) bool {
+	var value any
+	if c, ok := rec[expr.Column.Name]; ok {
+		_, value = columnValue(c)
+	}
+	switch expr.Operator {
+	case pb.ConditionalExpression_OPERATOR_IS_NULL:
+		return value == nil
+	case pb.ConditionalExpression_OPERATOR_IS_NOT_NULL:
+		return value != nil
+	}
+
+	_, operand := columnValue(expr.Column)
+	if value == nil || operand == nil {
+		return false
+	}
+	switch expr.Operator {
+	case pb.ConditionalExpression_OPERATOR_EQ:
+		return compareValues(value, operand) == 0
+	case pb.ConditionalExpression_OPERATOR_NE:
+		return compareValues(value, operand) != 0
+	case pb.ConditionalExpression_OPERATOR_GT:
+		return compareValues(value, operand) > 0
+	case pb.ConditionalExpression_OPERATOR_GTE:
+		return compareValues(value, operand) >= 0
+	case pb.ConditionalExpression_OPERATOR_LT:
+		return compareValues(value, operand) < 0
+	case pb.ConditionalExpression_OPERATOR_LTE:
+		return compareValues(value, operand) <= 0
+	case pb.ConditionalExpression_OPERATOR_LIKE:
+		return like(value.(string), operand.(string), expr.GetEscape())
+	case pb.ConditionalExpression_OPERATOR_NOT_LIKE:
+		return !like(value.(string), operand.(string), expr.GetEscape())
+	}
+	return false
+}
+
+// like reports whether a value matches a SQL LIKE pattern, where % matches any sequence,
+// _ matches one character and escape, a backslash by default, escapes them.
+func like(value, pattern, escape string) bool {
+	if escape == "" {
+		escape = `\`
+	}
+	var b strings.Builder
+	b.WriteString("(?s)^")
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+			escaped = false
+		case string(r) == escape:
+			escaped = true
+		case r == '%':
+			b.WriteString(".*")
+		case r == '_':
+			b.WriteString(".")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(r)))
+		}
+	}
+	b.WriteString("$")
+	return regexp.MustCompile(b.String()).MatchString(value)
+}
+
+// sortRecords sorts records by orderings, or by the clustering key in its clustering
+// order if there are none.
+func sortRecords(metadata *pb.TableMetadata, records []record, orderings []*pb.Scan_Ordering) {
+	if len(orderings) == 0 {
+		for _, name := range metadata.ClusteringKeyColumnNames {
+			order := pb.Scan_Ordering_ORDER_ASC
+			if metadata.ClusteringOrders[name] == pb.ClusteringOrder_CLUSTERING_ORDER_DESC {
+				order = pb.Scan_Ordering_ORDER_DESC
+			}
+			orderings = append(orderings, &pb.Scan_Ordering{ColumnName: name, Order: order})
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		for _, ordering := range orderings {
+			c := compareColumns(records[i][ordering.ColumnName], records[j][ordering.ColumnName])
+			if ordering.Order == pb.Scan_Ordering_ORDER_DESC {
+				c = -c
+			}
+			if c != 0 {
+				return c < 0
+			}
+		}
+		return false
+	})
+}
+
+// project returns a record as a result with the projected columns, or all columns if no
+// columns are projected. Columns that are not set are returned as null.
+func project(metadata *pb.TableMetadata, rec record, projections []string) *pb.Result {
+	if len(projections) == 0 {
+		projections = append(projections, metadata.PartitionKeyColumnNames...)
+		projections = append(projections, metadata.ClusteringKeyColumnNames...)
+		for _, name := range sortedKeys(metadata.Columns) {
+			if !slices.Contains(projections, name) {
+				projections = append(projections, name)
+			}
+		}
+	}
+	result := &pb.Result{}
+	for _, name := range projections {
+		c, ok := rec[name]
+		if !ok {
+			c = nullColumn(name, metadata.Columns[name])
+		}
+		result.Columns = append(result.Columns, clone(c))
+	}
+	return result
+}
+
+// checkProjections returns InvalidArgument unless the projected columns are defined.
+func checkProjections(metadata *pb.TableMetadata, projections []string) error {
+	for _, name := range projections {
+		if _, ok := metadata.Columns[name]; !ok {
+			return status.Errorf(codes.InvalidArgument, "projected column %s is not defined", name)
+		}
+	}
+	return nil
+}
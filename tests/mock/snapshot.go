@@ -0,0 +1,343 @@
+package mock
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// SnapshotVersion is the version of the snapshot format written by Save. Load rejects
+// snapshots of other versions.
+const SnapshotVersion = 1
+
+// snapshot is the JSON document that Save writes and Load reads. Protobuf messages are
+// encoded with protojson, and privileges by their names without the PRIVILEGE_ prefix.
+// Every section is optional, so a fixture can be written by hand.
+type snapshot struct {
+	Version           int                 `json:"version"`
+	Namespaces        []snapshotNamespace `json:"namespaces,omitempty"`
+	CoordinatorTables bool                `json:"coordinator_tables,omitempty"`
+	Users             []snapshotUser      `json:"users,omitempty"`
+	Privileges        []snapshotPrivilege `json:"privileges,omitempty"`
+	Policies          []snapshotPolicy    `json:"policies,omitempty"`
+	NamespacePolicies []json.RawMessage   `json:"namespace_policies,omitempty"`
+	TablePolicies     []json.RawMessage   `json:"table_policies,omitempty"`
+}
+
+// snapshotNamespace is a namespace and the metadata of its tables by table name.
+type snapshotNamespace struct {
+	Name   string                     `json:"name"`
+	Tables map[string]json.RawMessage `json:"tables,omitempty"`
+}
+
+type snapshotUser struct {
+	Name      string  `json:"name"`
+	Password  *string `json:"password,omitempty"`
+	Superuser bool    `json:"superuser,omitempty"`
+}
+
+type snapshotPrivilege struct {
+	Username   string   `json:"username"`
+	Namespace  string   `json:"namespace"`
+	Table      string   `json:"table,omitempty"`
+	Privileges []string `json:"privileges"`
+}
+
+type snapshotPolicy struct {
+	Policy       json.RawMessage   `json:"policy"`
+	Levels       []json.RawMessage `json:"levels,omitempty"`
+	Compartments []json.RawMessage `json:"compartments,omitempty"`
+	Groups       []json.RawMessage `json:"groups,omitempty"`
+	UserTags     []json.RawMessage `json:"user_tags,omitempty"`
+}
+
+// WithStateFile makes the server write a snapshot to a file after every request that
+// changes its state. Use Load to restore the snapshot when the server starts.
+func WithStateFile(path string) Option {
+	return func(s *Server) {
+		s.stateFile = path
+	}
+}
+
+// unlock releases mu after a change, writing the state file if there is one. The caller
+// must hold mu for writing.
+func (s *Server) unlock() {
+	if s.stateFile != "" {
+		if err := s.save(s.stateFile); err != nil {
+			s.logf("%v", err)
+		}
+	}
+	s.mu.Unlock()
+}
+
+// Save atomically writes a snapshot of the namespaces, tables, users, privileges and
+// policies of the server to a file. Auth tokens and injected faults are not saved.
+func (s *Server) Save(path string) error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.save(path)
+}
+
+// save writes a snapshot. The caller must hold mu.
+func (s *Server) save(path string) error {
+	data, err := json.MarshalIndent(s.snapshot(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+	if err := writeFileAtomic(path, append(data, '\n')); err != nil {
+		return fmt.Errorf("failed to write snapshot to %s: %w", path, err)
+	}
+	return nil
+}
+
+// snapshot returns the state of the server in snapshot form. The caller must hold mu.
+func (s *Server) snapshot() *snapshot {
+	snap := &snapshot{Version: SnapshotVersion, CoordinatorTables: s.coordinatorTables}
+
+	for _, namespace := range sortedKeys(s.namespaces) {
+		ns := snapshotNamespace{Name: namespace}
+		if len(s.tables[namespace]) > 0 {
+			ns.Tables = make(map[string]json.RawMessage)
+			for table, metadata := range s.tables[namespace] {
+				ns.Tables[table] = marshalMessage(metadata)
+			}
+		}
+		snap.Namespaces = append(snap.Namespaces, ns)
+	}
+
+	for _, name := range sortedKeys(s.users) {
+		user := s.users[name]
+		snap.Users = append(snap.Users, snapshotUser{Name: name, Password: user.password, Superuser: user.superuser})
+	}
+
+	for target, privileges := range s.privileges {
+		p := snapshotPrivilege{Username: target.username, Namespace: target.namespace, Table: target.table}
+		for privilege := range privileges {
+			p.Privileges = append(p.Privileges, strings.TrimPrefix(privilege.String(), "PRIVILEGE_"))
+		}
+		sort.Strings(p.Privileges)
+		snap.Privileges = append(snap.Privileges, p)
+	}
+	sort.Slice(snap.Privileges, func(i, j int) bool {
+		a, b := snap.Privileges[i], snap.Privileges[j]
+		if a.Username != b.Username {
+			return a.Username < b.Username
+		}
+		if a.Namespace != b.Namespace {
+			return a.Namespace < b.Namespace
+		}
+		return a.Table < b.Table
+	})
+
+	for _, name := range sortedKeys(s.policies) {
+		policy := s.policies[name]
+		snap.Policies = append(snap.Policies, snapshotPolicy{
+			Policy:       marshalMessage(policy.policy),
+			Levels:       marshalMessages(policy.levels),
+			Compartments: marshalMessages(policy.compartments),
+			Groups:       marshalMessages(policy.groups),
+			UserTags:     marshalMessages(policy.userTags),
+		})
+	}
+	snap.NamespacePolicies = marshalMessages(s.namespacePolicies)
+	snap.TablePolicies = marshalMessages(s.tablePolicies)
+	return snap
+}
+
+// Load replaces the state of the server with a snapshot read from a file. The default
+// superuser is created if the snapshot has no users. Records and open transactions are
+// discarded.
+func (s *Server) Load(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read snapshot: %w", err)
+	}
+	var snap snapshot
+	if err := json.Unmarshal(data, &snap); err != nil {
+		return fmt.Errorf("failed to decode snapshot %s: %w", path, err)
+	}
+	if snap.Version != SnapshotVersion {
+		return fmt.Errorf("snapshot %s has version %d, but only version %d is supported", path, snap.Version, SnapshotVersion)
+	}
+
+	loaded := NewServer()
+	if err := loaded.restore(&snap); err != nil {
+		return fmt.Errorf("failed to load snapshot %s: %w", path, err)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.namespaces = loaded.namespaces
+	s.tables = loaded.tables
+	s.coordinatorTables = loaded.coordinatorTables
+	s.users = loaded.users
+	s.privileges = loaded.privileges
+	s.policies = loaded.policies
+	s.namespacePolicies = loaded.namespacePolicies
+	s.tablePolicies = loaded.tablePolicies
+	s.records = loaded.records
+	s.transactions = loaded.transactions
+	return nil
+}
+
+// restore fills an empty server from a snapshot, validating it as the RPCs would.
+func (s *Server) restore(snap *snapshot) error {
+	s.coordinatorTables = snap.CoordinatorTables
+
+	for _, ns := range snap.Namespaces {
+		if err := requireName("namespace", ns.Name); err != nil {
+			return err
+		}
+		s.namespaces[ns.Name] = true
+		s.tables[ns.Name] = make(map[string]*pb.TableMetadata)
+		for table, data := range ns.Tables {
+			metadata := &pb.TableMetadata{}
+			if err := unmarshalMessage(data, metadata); err != nil {
+				return fmt.Errorf("table %s.%s: %w", ns.Name, table, err)
+			}
+			if err := validateTableMetadata(metadata); err != nil {
+				return fmt.Errorf("table %s.%s: %w", ns.Name, table, err)
+			}
+			s.tables[ns.Name][table] = metadata
+		}
+	}
+
+	if len(snap.Users) > 0 {
+		s.users = make(map[string]*mockUser)
+	}
+	for _, user := range snap.Users {
+		if err := requireName("user", user.Name); err != nil {
+			return err
+		}
+		s.users[user.Name] = &mockUser{password: user.Password, superuser: user.Superuser}
+	}
+
+	for _, p := range snap.Privileges {
+		var table *string
+		if p.Table != "" {
+			table = &p.Table
+		}
+		target, err := s.privilegeTarget(p.Username, p.Namespace, table)
+		if err != nil {
+			return err
+		}
+		s.privileges[target] = make(map[pb.Privilege]bool)
+		for _, name := range p.Privileges {
+			privilege, ok := pb.Privilege_value["PRIVILEGE_"+name]
+			if !ok || privilege == int32(pb.Privilege_PRIVILEGE_UNSPECIFIED) {
+				return fmt.Errorf("unknown privilege %s", name)
+			}
+			s.privileges[target][pb.Privilege(privilege)] = true
+		}
+	}
+
+	for _, p := range snap.Policies {
+		policy := &abacPolicy{
+			policy:       &pb.Policy{},
+			levels:       make(map[string]*pb.Level),
+			compartments: make(map[string]*pb.Compartment),
+			groups:       make(map[string]*pb.Group),
+			userTags:     make(map[string]*pb.UserTagInfo),
+		}
+		if err := unmarshalMessage(p.Policy, policy.policy); err != nil {
+			return fmt.Errorf("policy: %w", err)
+		}
+		if err := requireName("policy", policy.policy.Name); err != nil {
+			return err
+		}
+		if err := unmarshalMessages(p.Levels, policy.levels, (*pb.Level).GetShortName); err != nil {
+			return fmt.Errorf("levels of policy %s: %w", policy.policy.Name, err)
+		}
+		if err := unmarshalMessages(p.Compartments, policy.compartments, (*pb.Compartment).GetShortName); err != nil {
+			return fmt.Errorf("compartments of policy %s: %w", policy.policy.Name, err)
+		}
+		if err := unmarshalMessages(p.Groups, policy.groups, (*pb.Group).GetShortName); err != nil {
+			return fmt.Errorf("groups of policy %s: %w", policy.policy.Name, err)
+		}
+		if err := unmarshalMessages(p.UserTags, policy.userTags, (*pb.UserTagInfo).GetUsername); err != nil {
+			return fmt.Errorf("user tags of policy %s: %w", policy.policy.Name, err)
+		}
+		s.policies[policy.policy.Name] = policy
+	}
+	if err := unmarshalMessages(snap.NamespacePolicies, s.namespacePolicies, (*pb.NamespacePolicy).GetName); err != nil {
+		return fmt.Errorf("namespace policies: %w", err)
+	}
+	if err := unmarshalMessages(snap.TablePolicies, s.tablePolicies, (*pb.TablePolicy).GetName); err != nil {
+		return fmt.Errorf("table policies: %w", err)
+	}
+	return nil
+}
+
+// marshalMessage encodes a message with protojson. Messages of this package always encode.
+func marshalMessage(m proto.Message) json.RawMessage {
+	data, err := protojson.Marshal(m)
+	if err != nil {
+		panic(err)
+	}
+	return data
+}
+
+// marshalMessages encodes the messages of a map in the order of their keys.
+func marshalMessages[M proto.Message](messages map[string]M) []json.RawMessage {
+	var data []json.RawMessage
+	for _, key := range sortedKeys(messages) {
+		data = append(data, marshalMessage(messages[key]))
+	}
+	return data
+}
+
+// unmarshalMessage decodes a protojson message.
+func unmarshalMessage(data json.RawMessage, m proto.Message) error {
+	if err := protojson.Unmarshal(data, m); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", m.ProtoReflect().Descriptor().Name(), err)
+	}
+	return nil
+}
+
+// unmarshalMessages decodes protojson messages into a map by the key of each message.
+func unmarshalMessages[M interface {
+	proto.Message
+	*T
+}, T any](data []json.RawMessage, messages map[string]M, key func(M) string) error {
+	for _, d := range data {
+		m := M(new(T))
+		if err := unmarshalMessage(d, m); err != nil {
+			return err
+		}
+		if key(m) == "" {
+			return fmt.Errorf("%s has no name", m.ProtoReflect().Descriptor().Name())
+		}
+		messages[key(m)] = m
+	}
+	return nil
+}
+
+// writeFileAtomic writes a file by renaming a temporary file over it, so that readers
+// never see a partially written file.
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
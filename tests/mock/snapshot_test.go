@@ -0,0 +1,186 @@
+package mock
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+)
+
+func TestSnapshotRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "state.json")
+	server := NewServer(WithLogger(nil), WithStateFile(path))
+
+	password := "secret"
+	requests := []func() error{
+		func() error {
+			_, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"})
+			return err
+		},
+		func() error {
+			metadata := usersMetadata()
+			metadata.EncryptedColumns = []string{"email"}
+			_, err := server.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: "users", TableMetadata: metadata})
+			return err
+		},
+		func() error {
+			_, err := server.CreateIndex(ctx, &pb.CreateIndexRequest{NamespaceName: "ns", TableName: "users", ColumnName: "email"})
+			return err
+		},
+		func() error {
+			_, err := server.CreateUser(ctx, &pb.CreateUserRequest{Username: "alice", Password: &password})
+			return err
+		},
+		func() error {
+			_, err := server.Grant(ctx, &pb.GrantRequest{Username: "alice", NamespaceName: "ns", Privileges: []pb.Privilege{pb.Privilege_PRIVILEGE_READ}})
+			return err
+		},
+		func() error {
+			_, err := server.CreatePolicy(ctx, &pb.CreatePolicyRequest{PolicyName: "p"})
+			return err
+		},
+		func() error {
+			_, err := server.CreateLevel(ctx, &pb.CreateLevelRequest{PolicyName: "p", LevelShortName: "s", LevelLongName: "secret", LevelNumber: 1})
+			return err
+		},
+		func() error {
+			_, err := server.CreateNamespacePolicy(ctx, &pb.CreateNamespacePolicyRequest{NamespacePolicyName: "np", PolicyName: "p", NamespaceName: "ns"})
+			return err
+		},
+	}
+	for _, request := range requests {
+		if err := request(); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	saved, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("the state file was not written: %v", err)
+	}
+
+	loaded := NewServer(WithLogger(nil))
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	resaved := filepath.Join(t.TempDir(), "state.json")
+	if err := loaded.Save(resaved); err != nil {
+		t.Fatal(err)
+	}
+	got, err := os.ReadFile(resaved)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, saved) {
+		t.Errorf("snapshot changed after a round trip:\n%s\nwant:\n%s", got, saved)
+	}
+
+	resp, err := loaded.GetTableMetadata(ctx, &pb.GetTableMetadataRequest{NamespaceName: "ns", TableName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.TableMetadata.SecondaryIndexColumnNames) != 1 || len(resp.TableMetadata.EncryptedColumns) != 1 {
+		t.Errorf("got table metadata %v", resp.TableMetadata)
+	}
+	privileges, err := loaded.GetPrivileges(ctx, &pb.GetPrivilegesRequest{Username: "alice", NamespaceName: "ns"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(privileges.Privileges) != 1 || privileges.Privileges[0] != pb.Privilege_PRIVILEGE_READ {
+		t.Errorf("got privileges %v", privileges.Privileges)
+	}
+	if _, err := loaded.GetNamespacePolicy(ctx, &pb.GetNamespacePolicyRequest{NamespacePolicyName: "np"}); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestLoadFixture(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "fixture.json")
+	fixture := `{
+  "version": 1,
+  "namespaces": [
+    {
+      "name": "app",
+      "tables": {
+        "users": {
+          "columns": {"id": "DATA_TYPE_INT", "name": "DATA_TYPE_TEXT"},
+          "partitionKeyColumnNames": ["id"]
+        }
+      }
+    },
+    {"name": "empty"}
+  ]
+}`
+	if err := os.WriteFile(path, []byte(fixture), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	server := NewServer(WithLogger(nil))
+	if err := server.Load(path); err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	exists, err := server.TableExists(ctx, &pb.TableExistsRequest{NamespaceName: "app", TableName: "users"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !exists.Exists {
+		t.Error("table app.users was not loaded")
+	}
+	if _, err := server.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "empty", TableName: "orders", TableMetadata: usersMetadata()}); err != nil {
+		t.Errorf("failed to create a table in a loaded empty namespace: %v", err)
+	}
+	user, err := server.GetUser(ctx, &pb.GetUserRequest{Username: defaultUsername})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !user.GetUser().GetSuperuser() {
+		t.Error("the default superuser was not created")
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	tests := []struct {
+		name     string
+		snapshot string
+		want     string
+	}{
+		{
+			name:     "unsupported version",
+			snapshot: `{"version": 2}`,
+			want:     "only version 1 is supported",
+		},
+		{
+			name:     "invalid table",
+			snapshot: `{"version": 1, "namespaces": [{"name": "app", "tables": {"users": {"columns": {"id": "DATA_TYPE_INT"}}}}]}`,
+			want:     "partition key",
+		},
+		{
+			name:     "privilege of unknown user",
+			snapshot: `{"version": 1, "namespaces": [{"name": "app"}], "privileges": [{"username": "alice", "namespace": "app", "privileges": ["READ"]}]}`,
+			want:     "user alice does not exist",
+		},
+		{
+			name:     "unknown privilege",
+			snapshot: `{"version": 1, "namespaces": [{"name": "app"}], "privileges": [{"username": "admin", "namespace": "app", "privileges": ["EXECUTE"]}]}`,
+			want:     "unknown privilege EXECUTE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "state.json")
+			if err := os.WriteFile(path, []byte(tt.snapshot), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			err := NewServer(WithLogger(nil)).Load(path)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("got %v, want an error containing %q", err, tt.want)
+			}
+		})
+	}
+}
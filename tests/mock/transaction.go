@@ -0,0 +1,626 @@
+package mock
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// transaction is an open transaction. It reads the records committed up to its snapshot
+// and buffers its writes until it commits, which gives snapshot isolation.
+type transaction struct {
+	snapshot uint64
+	// writes are the records written by the transaction by table and primary key.
+	// A nil record marks a deletion.
+	writes map[tableName]map[string]record
+}
+
+// write buffers a write to a record.
+func (tx *transaction) write(table tableName, key string, rec record) {
+	if tx.writes == nil {
+		tx.writes = make(map[tableName]map[string]record)
+	}
+	if tx.writes[table] == nil {
+		tx.writes[table] = make(map[string]record)
+	}
+	tx.writes[table][key] = rec
+}
+
+// transactionServer implements the ScalarDB Cluster DistributedTransaction gRPC API on the
+// records of a Server. Records are stored per table by primary key, each with the versions
+// that open transactions may still read. Records are not written to the state file.
+type transactionServer struct {
+	pb.UnimplementedDistributedTransactionServer
+
+	s *Server
+}
+
+// Transactions returns the DistributedTransaction service of the server. Serve registers it
+// next to the admin service.
+func (s *Server) Transactions() pb.DistributedTransactionServer {
+	return &transactionServer{s: s}
+}
+
+// openTransaction returns the transaction with an ID, or a new one-shot transaction if no
+// ID is given. The caller must hold mu.
+func (s *Server) openTransaction(id *string) (tx *transaction, oneShot bool, err error) {
+	if id == nil || *id == "" {
+		return &transaction{snapshot: s.clock}, true, nil
+	}
+	tx, ok := s.transactions[*id]
+	if !ok {
+		return nil, false, status.Errorf(codes.NotFound, "transaction %s does not exist", *id)
+	}
+	return tx, false, nil
+}
+
+// visible returns the version of a record that a snapshot reads, or nil if there is none.
+func visible(versions []recordVersion, snapshot uint64) record {
+	for i := len(versions) - 1; i >= 0; i-- {
+		if versions[i].commitTS <= snapshot {
+			return versions[i].record
+		}
+	}
+	return nil
+}
+
+// read returns a record as a transaction sees it, or nil if it does not exist. The caller
+// must hold mu.
+func (s *Server) read(tx *transaction, table tableName, key string) record {
+	if rec, ok := tx.writes[table][key]; ok {
+		return rec
+	}
+	return visible(s.records[table][key], tx.snapshot)
+}
+
+// readAll returns the records of a table as a transaction sees them, in the order of
+// their primary keys. The caller must hold mu.
+func (s *Server) readAll(tx *transaction, table tableName) []record {
+	records := make(map[string]record)
+	for key, versions := range s.records[table] {
+		if rec := visible(versions, tx.snapshot); rec != nil {
+			records[key] = rec
+		}
+	}
+	for key, rec := range tx.writes[table] {
+		if rec == nil {
+			delete(records, key)
+		} else {
+			records[key] = rec
+		}
+	}
+	var sorted []record
+	for _, key := range sortedKeys(records) {
+		sorted = append(sorted, records[key])
+	}
+	return sorted
+}
+
+// indexRecords returns the records whose indexed column equals the only column of an
+// index key. The caller must hold mu.
+func (s *Server) indexRecords(tx *transaction, table tableName, metadata *pb.TableMetadata, key *pb.Key) ([]record, error) {
+	if len(key.GetColumns()) != 1 {
+		return nil, status.Error(codes.InvalidArgument, "an index key must have exactly one column")
+	}
+	column := key.Columns[0]
+	if err := checkColumn(metadata, column); err != nil {
+		return nil, err
+	}
+	if !slices.Contains(metadata.SecondaryIndexColumnNames, column.Name) {
+		return nil, status.Errorf(codes.InvalidArgument, "column %s of table %s is not indexed", column.Name, table)
+	}
+	if _, v := columnValue(column); v == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "index key column %s must not be null", column.Name)
+	}
+
+	var matches []record
+	for _, rec := range s.readAll(tx, table) {
+		if _, v := columnValue(rec[column.Name]); v != nil && compareColumns(rec[column.Name], column) == 0 {
+			matches = append(matches, rec)
+		}
+	}
+	return matches, nil
+}
+
+// commit writes the changes of a transaction as new record versions. It returns Aborted
+// if another transaction committed a record it writes after its snapshot, so the first
+// committer wins. The caller must hold mu.
+func (s *Server) commit(tx *transaction) error {
+	for table, writes := range tx.writes {
+		if _, err := s.lookupTable(table.namespace, table.table); err != nil {
+			return status.Errorf(codes.Aborted, "table %s was dropped during the transaction", table)
+		}
+		for key := range writes {
+			versions := s.records[table][key]
+			if n := len(versions); n > 0 && versions[n-1].commitTS > tx.snapshot {
+				return status.Errorf(codes.Aborted, "a record of table %s was changed by a concurrent transaction", table)
+			}
+		}
+	}
+	if len(tx.writes) == 0 {
+		return nil
+	}
+
+	s.clock++
+	for table, writes := range tx.writes {
+		if s.records[table] == nil {
+			s.records[table] = make(map[string][]recordVersion)
+		}
+		for key, rec := range writes {
+			s.records[table][key] = append(s.records[table][key], recordVersion{commitTS: s.clock, record: rec})
+		}
+	}
+	s.pruneVersions(tx)
+	return nil
+}
+
+// pruneVersions drops the versions of the records written by a transaction that no open
+// transaction can read anymore. The caller must hold mu.
+func (s *Server) pruneVersions(tx *transaction) {
+	oldest := s.clock
+	for _, open := range s.transactions {
+		oldest = min(oldest, open.snapshot)
+	}
+	for table, writes := range tx.writes {
+		for key := range writes {
+			versions := s.records[table][key]
+			first := 0
+			for i, version := range versions {
+				if version.commitTS <= oldest {
+					first = i
+				}
+			}
+			versions = versions[first:]
+			if len(versions) == 1 && versions[0].record == nil {
+				delete(s.records[table], key)
+				continue
+			}
+			s.records[table][key] = versions
+		}
+	}
+}
+
+// mutationKind is the kind of a mutation.
+type mutationKind int
+
+const (
+	mutationPut mutationKind = iota
+	mutationInsert
+	mutationUpsert
+	mutationUpdate
+	mutationDelete
+)
+
+// mutation is a Put, Insert, Upsert, Update or Delete.
+type mutation struct {
+	kind          mutationKind
+	namespace     string
+	table         string
+	partitionKey  *pb.Key
+	clusteringKey *pb.Key
+	columns       []*pb.Column
+
+	// ifExists and ifNotExists require the record to exist or not to exist, and
+	// expressions, if any, must hold for the existing record.
+	ifExists    bool
+	ifNotExists bool
+	expressions []*pb.ConditionalExpression
+}
+
+func putMutation(p *pb.Put) mutation {
+	m := mutation{kind: mutationPut, namespace: p.NamespaceName, table: p.TableName,
+		partitionKey: p.PartitionKey, clusteringKey: p.ClusteringKey, columns: p.Columns}
+	switch p.GetPutCondition().GetPutConditionType() {
+	case pb.Put_PutCondition_PUT_CONDITION_TYPE_IF:
+		m.ifExists, m.expressions = true, p.PutCondition.ConditionalExpressions
+	case pb.Put_PutCondition_PUT_CONDITION_TYPE_IF_EXISTS:
+		m.ifExists = true
+	case pb.Put_PutCondition_PUT_CONDITION_TYPE_IF_NOT_EXISTS:
+		m.ifNotExists = true
+	}
+	return m
+}
+
+func insertMutation(i *pb.Insert) mutation {
+	return mutation{kind: mutationInsert, namespace: i.NamespaceName, table: i.TableName,
+		partitionKey: i.PartitionKey, clusteringKey: i.ClusteringKey, columns: i.Columns}
+}
+
+func upsertMutation(u *pb.Upsert) mutation {
+	return mutation{kind: mutationUpsert, namespace: u.NamespaceName, table: u.TableName,
+		partitionKey: u.PartitionKey, clusteringKey: u.ClusteringKey, columns: u.Columns}
+}
+
+func updateMutation(u *pb.Update) mutation {
+	m := mutation{kind: mutationUpdate, namespace: u.NamespaceName, table: u.TableName,
+		partitionKey: u.PartitionKey, clusteringKey: u.ClusteringKey, columns: u.Columns}
+	switch u.GetUpdateCondition().GetUpdateConditionType() {
+	case pb.Update_UpdateCondition_UPDATE_CONDITION_TYPE_IF:
+		m.ifExists, m.expressions = true, u.UpdateCondition.ConditionalExpressions
+	case pb.Update_UpdateCondition_UPDATE_CONDITION_TYPE_IF_EXISTS:
+		m.ifExists = true
+	}
+	return m
+}
+
+func deleteMutation(d *pb.Delete) mutation {
+	m := mutation{kind: mutationDelete, namespace: d.NamespaceName, table: d.TableName,
+		partitionKey: d.PartitionKey, clusteringKey: d.ClusteringKey}
+	switch d.GetDeleteCondition().GetDeleteConditionType() {
+	case pb.Delete_DeleteCondition_DELETE_CONDITION_TYPE_IF:
+		m.ifExists, m.expressions = true, d.DeleteCondition.ConditionalExpressions
+	case pb.Delete_DeleteCondition_DELETE_CONDITION_TYPE_IF_EXISTS:
+		m.ifExists = true
+	}
+	return m
+}
+
+// mutationOf converts a Mutation, or returns InvalidArgument if it is empty.
+func mutationOf(m *pb.Mutation) (mutation, error) {
+	switch m := m.GetMutation().(type) {
+	case *pb.Mutation_Put:
+		return putMutation(m.Put), nil
+	case *pb.Mutation_Insert:
+		return insertMutation(m.Insert), nil
+	case *pb.Mutation_Upsert:
+		return upsertMutation(m.Upsert), nil
+	case *pb.Mutation_Update:
+		return updateMutation(m.Update), nil
+	case *pb.Mutation_Delete:
+		return deleteMutation(m.Delete), nil
+	}
+	return mutation{}, status.Error(codes.InvalidArgument, "mutation is not specified")
+}
+
+// mutate applies a mutation to a transaction. It returns AlreadyExists if an inserted
+// record exists and FailedPrecondition if a condition is not satisfied. The caller must
+// hold mu for writing.
+func (s *Server) mutate(tx *transaction, m mutation) error {
+	table := tableName{m.namespace, m.table}
+	metadata, err := s.lookupTable(m.namespace, m.table)
+	if err != nil {
+		return err
+	}
+	keyRec, key, err := primaryKey(metadata, m.partitionKey, m.clusteringKey)
+	if err != nil {
+		return err
+	}
+	for _, c := range m.columns {
+		if err := checkColumn(metadata, c); err != nil {
+			return err
+		}
+		if _, isKey := keyRec[c.Name]; isKey {
+			return status.Errorf(codes.InvalidArgument, "key column %s cannot be written as a value", c.Name)
+		}
+	}
+	if err := checkExpressions(metadata, m.expressions); err != nil {
+		return err
+	}
+
+	existing := s.read(tx, table, key)
+	switch {
+	case m.kind == mutationInsert && existing != nil:
+		return status.Errorf(codes.AlreadyExists, "the record already exists in table %s", table)
+	case m.ifNotExists && existing != nil:
+		return status.Errorf(codes.FailedPrecondition, "the condition is not satisfied: the record exists in table %s", table)
+	case m.ifExists && existing == nil:
+		return status.Errorf(codes.FailedPrecondition, "the condition is not satisfied: the record does not exist in table %s", table)
+	case len(m.expressions) > 0 && !matchesExpressions(existing, m.expressions):
+		return status.Errorf(codes.FailedPrecondition, "the condition is not satisfied for the record in table %s", table)
+	}
+
+	switch {
+	case m.kind == mutationDelete:
+		tx.write(table, key, nil)
+		return nil
+	case m.kind == mutationUpdate && existing == nil:
+		// Update does nothing if the record does not exist.
+		return nil
+	}
+
+	rec := make(record)
+	base := existing
+	if base == nil {
+		base = keyRec
+	}
+	for name, c := range base {
+		rec[name] = c
+	}
+	for _, c := range m.columns {
+		rec[c.Name] = clone(c)
+	}
+	tx.write(table, key, rec)
+	return nil
+}
+
+// applyMutations applies mutations to a transaction, committing them at once if no
+// transaction ID is given.
+func (s *Server) applyMutations(id *string, mutations []mutation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, oneShot, err := s.openTransaction(id)
+	if err != nil {
+		return err
+	}
+	for _, m := range mutations {
+		if err := s.mutate(tx, m); err != nil {
+			return err
+		}
+	}
+	if oneShot {
+		return s.commit(tx)
+	}
+	return nil
+}
+
+// Begin implements the Begin RPC.
+func (t *transactionServer) Begin(ctx context.Context, req *pb.BeginRequest) (*pb.BeginResponse, error) {
+	s := t.s
+	s.logf("Begin: %v", req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	id := req.GetTransactionId()
+	if id == "" {
+		for id == "" || s.transactions[id] != nil {
+			s.lastTransactionID++
+			id = fmt.Sprintf("tx-%d", s.lastTransactionID)
+		}
+	} else if _, exists := s.transactions[id]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "transaction %s already exists", id)
+	}
+	s.transactions[id] = &transaction{snapshot: s.clock}
+	return &pb.BeginResponse{TransactionId: id}, nil
+}
+
+// Get implements the Get RPC.
+func (t *transactionServer) Get(ctx context.Context, req *pb.GetRequest) (*pb.GetResponse, error) {
+	s := t.s
+	s.logf("Get: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tx, _, err := s.openTransaction(req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	get := req.GetGet()
+	table := tableName{get.GetNamespaceName(), get.GetTableName()}
+	metadata, err := s.lookupTable(table.namespace, table.table)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjections(metadata, get.Projections); err != nil {
+		return nil, err
+	}
+	for _, conjunction := range get.Conjunctions {
+		if err := checkExpressions(metadata, conjunction.ConditionalExpressions); err != nil {
+			return nil, err
+		}
+	}
+
+	var rec record
+	if get.GetType == pb.Get_GET_TYPE_GET_WITH_INDEX {
+		matches, err := s.indexRecords(tx, table, metadata, get.PartitionKey)
+		if err != nil {
+			return nil, err
+		}
+		if len(matches) > 1 {
+			return nil, status.Errorf(codes.InvalidArgument, "the index key matches more than one record in table %s; use Scan instead", table)
+		}
+		if len(matches) == 1 {
+			rec = matches[0]
+		}
+	} else {
+		_, key, err := primaryKey(metadata, get.PartitionKey, get.ClusteringKey)
+		if err != nil {
+			return nil, err
+		}
+		rec = s.read(tx, table, key)
+	}
+
+	if rec == nil || !matchesConjunctions(rec, get.Conjunctions) {
+		return &pb.GetResponse{}, nil
+	}
+	return &pb.GetResponse{Result: project(metadata, rec, get.Projections)}, nil
+}
+
+// Scan implements the Scan RPC.
+func (t *transactionServer) Scan(ctx context.Context, req *pb.ScanRequest) (*pb.ScanResponse, error) {
+	s := t.s
+	s.logf("Scan: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	tx, _, err := s.openTransaction(req.TransactionId)
+	if err != nil {
+		return nil, err
+	}
+	scan := req.GetScan()
+	table := tableName{scan.GetNamespaceName(), scan.GetTableName()}
+	metadata, err := s.lookupTable(table.namespace, table.table)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkProjections(metadata, scan.Projections); err != nil {
+		return nil, err
+	}
+	for _, conjunction := range scan.Conjunctions {
+		if err := checkExpressions(metadata, conjunction.ConditionalExpressions); err != nil {
+			return nil, err
+		}
+	}
+	for _, ordering := range scan.Orderings {
+		if _, ok := metadata.Columns[ordering.ColumnName]; !ok {
+			return nil, status.Errorf(codes.InvalidArgument, "ordering column %s is not defined", ordering.ColumnName)
+		}
+	}
+
+	var records []record
+	switch scan.ScanType {
+	case pb.Scan_SCAN_TYPE_SCAN_WITH_INDEX:
+		if records, err = s.indexRecords(tx, table, metadata, scan.PartitionKey); err != nil {
+			return nil, err
+		}
+	case pb.Scan_SCAN_TYPE_SCAN_ALL:
+		records = s.readAll(tx, table)
+	default:
+		partition, err := keyRecord(metadata, scan.PartitionKey, metadata.PartitionKeyColumnNames, "partition key")
+		if err != nil {
+			return nil, err
+		}
+		for _, key := range []*pb.Key{scan.StartClusteringKey, scan.EndClusteringKey} {
+			if err := checkClusteringPrefix(metadata, key); err != nil {
+				return nil, err
+			}
+		}
+		for _, rec := range s.readAll(tx, table) {
+			if !matchesPartition(metadata, rec, partition) {
+				continue
+			}
+			if scan.StartClusteringKey != nil {
+				if c := compareClusteringPrefix(metadata, rec, scan.StartClusteringKey); c < 0 || c == 0 && !scan.StartInclusive {
+					continue
+				}
+			}
+			if scan.EndClusteringKey != nil {
+				if c := compareClusteringPrefix(metadata, rec, scan.EndClusteringKey); c > 0 || c == 0 && !scan.EndInclusive {
+					continue
+				}
+			}
+			records = append(records, rec)
+		}
+	}
+
+	sortRecords(metadata, records, scan.Orderings)
+	resp := &pb.ScanResponse{}
+	for _, rec := range records {
+		if !matchesConjunctions(rec, scan.Conjunctions) {
+			continue
+		}
+		resp.Results = append(resp.Results, project(metadata, rec, scan.Projections))
+		if scan.Limit > 0 && len(resp.Results) == int(scan.Limit) {
+			break
+		}
+	}
+	return resp, nil
+}
+
+// Put implements the deprecated Put RPC.
+func (t *transactionServer) Put(ctx context.Context, req *pb.PutRequest) (*pb.PutResponse, error) {
+	t.s.logf("Put: %v", req)
+	var mutations []mutation
+	for _, put := range req.Puts {
+		mutations = append(mutations, putMutation(put))
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.PutResponse{}, nil
+}
+
+// Insert implements the Insert RPC.
+func (t *transactionServer) Insert(ctx context.Context, req *pb.InsertRequest) (*pb.InsertResponse, error) {
+	t.s.logf("Insert: %v", req)
+	var mutations []mutation
+	for _, insert := range req.Inserts {
+		mutations = append(mutations, insertMutation(insert))
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.InsertResponse{}, nil
+}
+
+// Upsert implements the Upsert RPC.
+func (t *transactionServer) Upsert(ctx context.Context, req *pb.UpsertRequest) (*pb.UpsertResponse, error) {
+	t.s.logf("Upsert: %v", req)
+	var mutations []mutation
+	for _, upsert := range req.Upserts {
+		mutations = append(mutations, upsertMutation(upsert))
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.UpsertResponse{}, nil
+}
+
+// Update implements the Update RPC.
+func (t *transactionServer) Update(ctx context.Context, req *pb.UpdateRequest) (*pb.UpdateResponse, error) {
+	t.s.logf("Update: %v", req)
+	var mutations []mutation
+	for _, update := range req.Updates {
+		mutations = append(mutations, updateMutation(update))
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.UpdateResponse{}, nil
+}
+
+// Delete implements the Delete RPC.
+func (t *transactionServer) Delete(ctx context.Context, req *pb.DeleteRequest) (*pb.DeleteResponse, error) {
+	t.s.logf("Delete: %v", req)
+	var mutations []mutation
+	for _, del := range req.Deletes {
+		mutations = append(mutations, deleteMutation(del))
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.DeleteResponse{}, nil
+}
+
+// Mutate implements the Mutate RPC.
+func (t *transactionServer) Mutate(ctx context.Context, req *pb.MutateRequest) (*pb.MutateResponse, error) {
+	t.s.logf("Mutate: %v", req)
+	var mutations []mutation
+	for _, m := range req.Mutations {
+		converted, err := mutationOf(m)
+		if err != nil {
+			return nil, err
+		}
+		mutations = append(mutations, converted)
+	}
+	if err := t.s.applyMutations(req.TransactionId, mutations); err != nil {
+		return nil, err
+	}
+	return &pb.MutateResponse{}, nil
+}
+
+// Commit implements the Commit RPC. The transaction ends whether or not it commits.
+func (t *transactionServer) Commit(ctx context.Context, req *pb.CommitRequest) (*pb.CommitResponse, error) {
+	s := t.s
+	s.logf("Commit: %v", req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	tx, ok := s.transactions[req.TransactionId]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s does not exist", req.TransactionId)
+	}
+	delete(s.transactions, req.TransactionId)
+	if err := s.commit(tx); err != nil {
+		return nil, err
+	}
+	return &pb.CommitResponse{}, nil
+}
+
+// Rollback implements the Rollback RPC.
+func (t *transactionServer) Rollback(ctx context.Context, req *pb.RollbackRequest) (*pb.RollbackResponse, error) {
+	s := t.s
+	s.logf("Rollback: %v", req)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, ok := s.transactions[req.TransactionId]; !ok {
+		return nil, status.Errorf(codes.NotFound, "transaction %s does not exist", req.TransactionId)
+	}
+	delete(s.transactions, req.TransactionId)
+	return &pb.RollbackResponse{}, nil
+}
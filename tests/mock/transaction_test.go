@@ -0,0 +1,377 @@
+package mock
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func intColumn(name string, value int32) *pb.Column {
+	return &pb.Column{Name: name, Value: &pb.Column_IntValue_{IntValue: &pb.Column_IntValue{Value: &value}}}
+}
+
+func textColumn(name, value string) *pb.Column {
+	return &pb.Column{Name: name, Value: &pb.Column_TextValue_{TextValue: &pb.Column_TextValue{Value: &value}}}
+}
+
+func key(columns ...*pb.Column) *pb.Key {
+	return &pb.Key{Columns: columns}
+}
+
+// columnOf returns a column of a result, or nil.
+func columnOf(result *pb.Result, name string) *pb.Column {
+	for _, c := range result.GetColumns() {
+		if c.Name == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// intsOf returns an int column of each result.
+func intsOf(results []*pb.Result, name string) []int32 {
+	var values []int32
+	for _, result := range results {
+		values = append(values, columnOf(result, name).GetIntValue().GetValue())
+	}
+	return values
+}
+
+// testTransactionClient starts a server with an orders table on bufconn and returns
+// the server and a client of its DistributedTransaction service.
+func testTransactionClient(t *testing.T) (*Instance, pb.DistributedTransactionClient) {
+	t.Helper()
+	server := ListenBufconn(WithLogger(nil))
+	t.Cleanup(server.Stop)
+	conn, err := server.Dial()
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	ctx := context.Background()
+	if _, err := server.CreateNamespace(ctx, &pb.CreateNamespaceRequest{NamespaceName: "ns"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.CreateTable(ctx, &pb.CreateTableRequest{NamespaceName: "ns", TableName: "orders", TableMetadata: &pb.TableMetadata{
+		Columns: map[string]pb.DataType{
+			"customer": pb.DataType_DATA_TYPE_INT,
+			"seq":      pb.DataType_DATA_TYPE_INT,
+			"item":     pb.DataType_DATA_TYPE_TEXT,
+			"amount":   pb.DataType_DATA_TYPE_INT,
+		},
+		PartitionKeyColumnNames:   []string{"customer"},
+		ClusteringKeyColumnNames:  []string{"seq"},
+		SecondaryIndexColumnNames: []string{"item"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	return server, pb.NewDistributedTransactionClient(conn)
+}
+
+func insertOrder(customer, seq int32, item string, amount int32) *pb.Insert {
+	return &pb.Insert{
+		NamespaceName: "ns", TableName: "orders",
+		PartitionKey: key(intColumn("customer", customer)), ClusteringKey: key(intColumn("seq", seq)),
+		Columns: []*pb.Column{textColumn("item", item), intColumn("amount", amount)},
+	}
+}
+
+func getOrder(customer, seq int32) *pb.Get {
+	return &pb.Get{
+		NamespaceName: "ns", TableName: "orders", GetType: pb.Get_GET_TYPE_GET,
+		PartitionKey: key(intColumn("customer", customer)), ClusteringKey: key(intColumn("seq", seq)),
+	}
+}
+
+func TestTransactionCRUD(t *testing.T) {
+	_, client := testTransactionClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Insert(ctx, &pb.InsertRequest{Inserts: []*pb.Insert{insertOrder(1, 1, "apple", 3)}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err := client.Insert(ctx, &pb.InsertRequest{Inserts: []*pb.Insert{insertOrder(1, 1, "apple", 3)}})
+	if status.Code(err) != codes.AlreadyExists {
+		t.Errorf("inserting an existing record: got %v, want AlreadyExists", err)
+	}
+
+	got, err := client.Get(ctx, &pb.GetRequest{Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amounts := intsOf([]*pb.Result{got.Result}, "amount"); !slices.Equal(amounts, []int32{3}) {
+		t.Errorf("got %v", got.Result)
+	}
+
+	// A failed condition aborts the update.
+	update := &pb.Update{
+		NamespaceName: "ns", TableName: "orders",
+		PartitionKey: key(intColumn("customer", 1)), ClusteringKey: key(intColumn("seq", 1)),
+		Columns: []*pb.Column{intColumn("amount", 5)},
+		UpdateCondition: &pb.Update_UpdateCondition{
+			UpdateConditionType: pb.Update_UpdateCondition_UPDATE_CONDITION_TYPE_IF,
+			ConditionalExpressions: []*pb.ConditionalExpression{
+				{Column: intColumn("amount", 4), Operator: pb.ConditionalExpression_OPERATOR_EQ},
+			},
+		},
+	}
+	_, err = client.Update(ctx, &pb.UpdateRequest{Updates: []*pb.Update{update}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("update with a failed condition: got %v, want FailedPrecondition", err)
+	}
+	update.UpdateCondition.ConditionalExpressions[0].Column = intColumn("amount", 3)
+	if _, err := client.Update(ctx, &pb.UpdateRequest{Updates: []*pb.Update{update}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if amounts := intsOf([]*pb.Result{got.Result}, "amount"); !slices.Equal(amounts, []int32{5}) {
+		t.Errorf("the update was not applied: got %v", got.Result)
+	}
+	if item := columnOf(got.Result, "item"); item.GetTextValue().GetValue() != "apple" {
+		t.Errorf("the update did not keep the other columns: got %v", got.Result)
+	}
+
+	del := &pb.Delete{
+		NamespaceName: "ns", TableName: "orders",
+		PartitionKey: key(intColumn("customer", 1)), ClusteringKey: key(intColumn("seq", 1)),
+		DeleteCondition: &pb.Delete_DeleteCondition{DeleteConditionType: pb.Delete_DeleteCondition_DELETE_CONDITION_TYPE_IF_EXISTS},
+	}
+	if _, err := client.Delete(ctx, &pb.DeleteRequest{Deletes: []*pb.Delete{del}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Delete(ctx, &pb.DeleteRequest{Deletes: []*pb.Delete{del}})
+	if status.Code(err) != codes.FailedPrecondition {
+		t.Errorf("deleting a missing record if it exists: got %v, want FailedPrecondition", err)
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Result != nil {
+		t.Errorf("the record was not deleted: got %v", got.Result)
+	}
+}
+
+func TestTransactionScan(t *testing.T) {
+	_, client := testTransactionClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Insert(ctx, &pb.InsertRequest{Inserts: []*pb.Insert{
+		insertOrder(1, 3, "cherry", 30),
+		insertOrder(1, 1, "apple", 10),
+		insertOrder(1, 2, "banana", 20),
+		insertOrder(1, 4, "apricot", 40),
+		insertOrder(2, 1, "apple", 50),
+	}}); err != nil {
+		t.Fatal(err)
+	}
+
+	scanOf := func(scan *pb.Scan) *pb.Scan {
+		scan.NamespaceName, scan.TableName = "ns", "orders"
+		if scan.ScanType == pb.Scan_SCAN_TYPE_UNSPECIFIED {
+			scan.ScanType = pb.Scan_SCAN_TYPE_SCAN
+			scan.PartitionKey = key(intColumn("customer", 1))
+		}
+		return scan
+	}
+	like := func(pattern string) *pb.Conjunction {
+		return &pb.Conjunction{ConditionalExpressions: []*pb.ConditionalExpression{
+			{Column: textColumn("item", pattern), Operator: pb.ConditionalExpression_OPERATOR_LIKE},
+		}}
+	}
+	tests := []struct {
+		name   string
+		scan   *pb.Scan
+		column string
+		want   []int32
+	}{
+		{
+			name:   "clustering order",
+			scan:   scanOf(&pb.Scan{}),
+			column: "seq",
+			want:   []int32{1, 2, 3, 4},
+		},
+		{
+			name: "range",
+			scan: scanOf(&pb.Scan{
+				StartClusteringKey: key(intColumn("seq", 2)), StartInclusive: true,
+				EndClusteringKey: key(intColumn("seq", 4)),
+			}),
+			column: "seq",
+			want:   []int32{2, 3},
+		},
+		{
+			name: "descending with limit",
+			scan: scanOf(&pb.Scan{
+				Orderings: []*pb.Scan_Ordering{{ColumnName: "seq", Order: pb.Scan_Ordering_ORDER_DESC}},
+				Limit:     2,
+			}),
+			column: "seq",
+			want:   []int32{4, 3},
+		},
+		{
+			name:   "conjunctions",
+			scan:   scanOf(&pb.Scan{Conjunctions: []*pb.Conjunction{like("ap%"), like("%an%")}}),
+			column: "seq",
+			want:   []int32{1, 2, 4},
+		},
+		{
+			name: "greater than",
+			scan: scanOf(&pb.Scan{Conjunctions: []*pb.Conjunction{{ConditionalExpressions: []*pb.ConditionalExpression{
+				{Column: intColumn("amount", 20), Operator: pb.ConditionalExpression_OPERATOR_GT},
+			}}}}),
+			column: "amount",
+			want:   []int32{30, 40},
+		},
+		{
+			name: "index",
+			scan: scanOf(&pb.Scan{
+				ScanType: pb.Scan_SCAN_TYPE_SCAN_WITH_INDEX, PartitionKey: key(textColumn("item", "apple")),
+			}),
+			column: "amount",
+			want:   []int32{10, 50},
+		},
+		{
+			name: "all",
+			scan: scanOf(&pb.Scan{
+				ScanType:  pb.Scan_SCAN_TYPE_SCAN_ALL,
+				Orderings: []*pb.Scan_Ordering{{ColumnName: "amount", Order: pb.Scan_Ordering_ORDER_DESC}},
+			}),
+			column: "amount",
+			want:   []int32{50, 40, 30, 20, 10},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := client.Scan(ctx, &pb.ScanRequest{Scan: tt.scan})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := intsOf(resp.Results, tt.column); !slices.Equal(got, tt.want) {
+				t.Errorf("got %s %v, want %v", tt.column, got, tt.want)
+			}
+		})
+	}
+
+	_, err := client.Get(ctx, &pb.GetRequest{Get: &pb.Get{
+		NamespaceName: "ns", TableName: "orders", GetType: pb.Get_GET_TYPE_GET_WITH_INDEX,
+		PartitionKey: key(textColumn("item", "apple")),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("getting by an index key of two records: got %v, want InvalidArgument", err)
+	}
+	_, err = client.Scan(ctx, &pb.ScanRequest{Scan: &pb.Scan{
+		NamespaceName: "ns", TableName: "orders", ScanType: pb.Scan_SCAN_TYPE_SCAN_WITH_INDEX,
+		PartitionKey: key(intColumn("amount", 10)),
+	}})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Errorf("scanning by a column without an index: got %v, want InvalidArgument", err)
+	}
+}
+
+func TestTransactionIsolation(t *testing.T) {
+	_, client := testTransactionClient(t)
+	ctx := context.Background()
+	begin := func() *string {
+		t.Helper()
+		resp, err := client.Begin(ctx, &pb.BeginRequest{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return proto.String(resp.TransactionId)
+	}
+
+	tx1, tx2 := begin(), begin()
+	if _, err := client.Insert(ctx, &pb.InsertRequest{TransactionId: tx1, Inserts: []*pb.Insert{insertOrder(1, 1, "apple", 10)}}); err != nil {
+		t.Fatal(err)
+	}
+	got, err := client.Get(ctx, &pb.GetRequest{TransactionId: tx1, Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Result == nil {
+		t.Error("a transaction does not read its own write")
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Result != nil {
+		t.Error("an uncommitted write is visible outside its transaction")
+	}
+
+	if _, err := client.Commit(ctx, &pb.CommitRequest{TransactionId: *tx1}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{TransactionId: tx2, Get: getOrder(1, 1)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Result != nil {
+		t.Error("a write committed after a transaction began is visible in it")
+	}
+
+	// tx2 writes the record that tx1 committed after tx2 began.
+	if _, err := client.Upsert(ctx, &pb.UpsertRequest{TransactionId: tx2, Upserts: []*pb.Upsert{{
+		NamespaceName: "ns", TableName: "orders",
+		PartitionKey: key(intColumn("customer", 1)), ClusteringKey: key(intColumn("seq", 1)),
+		Columns: []*pb.Column{intColumn("amount", 20)},
+	}}}); err != nil {
+		t.Fatal(err)
+	}
+	_, err = client.Commit(ctx, &pb.CommitRequest{TransactionId: *tx2})
+	if status.Code(err) != codes.Aborted {
+		t.Errorf("committing a conflicting write: got %v, want Aborted", err)
+	}
+	_, err = client.Rollback(ctx, &pb.RollbackRequest{TransactionId: *tx2})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("rolling back an ended transaction: got %v, want NotFound", err)
+	}
+
+	tx3 := begin()
+	if _, err := client.Insert(ctx, &pb.InsertRequest{TransactionId: tx3, Inserts: []*pb.Insert{insertOrder(1, 2, "banana", 10)}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := client.Rollback(ctx, &pb.RollbackRequest{TransactionId: *tx3}); err != nil {
+		t.Fatal(err)
+	}
+	got, err = client.Get(ctx, &pb.GetRequest{Get: getOrder(1, 2)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.Result != nil {
+		t.Error("a rolled back write is visible")
+	}
+	_, err = client.Get(ctx, &pb.GetRequest{TransactionId: tx3, Get: getOrder(1, 2)})
+	if status.Code(err) != codes.NotFound {
+		t.Errorf("reading in a rolled back transaction: got %v, want NotFound", err)
+	}
+}
+
+func TestTruncateTableDeletesRecords(t *testing.T) {
+	server, client := testTransactionClient(t)
+	ctx := context.Background()
+
+	if _, err := client.Insert(ctx, &pb.InsertRequest{Inserts: []*pb.Insert{insertOrder(1, 1, "apple", 10)}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := server.TruncateTable(ctx, &pb.TruncateTableRequest{NamespaceName: "ns", TableName: "orders"}); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := client.Scan(ctx, &pb.ScanRequest{Scan: &pb.Scan{NamespaceName: "ns", TableName: "orders", ScanType: pb.Scan_SCAN_TYPE_SCAN_ALL}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(resp.Results) != 0 {
+		t.Errorf("got %d records after truncating the table", len(resp.Results))
+	}
+}
@@ -0,0 +1,236 @@
+package mock
+
+import (
+	"context"
+	"sort"
+
+	pb "github.com/scalar-labs/terraform-provider-scalardb/proto/scalardb"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// defaultUsername is the superuser that exists when the server starts, as in ScalarDB Cluster.
+const defaultUsername = "admin"
+
+// mockUser is a user of the mock server.
+type mockUser struct {
+	password  *string
+	superuser bool
+}
+
+// privilegeTarget is a user and the namespace or table that privileges are granted on.
+// table is empty for privileges on a namespace.
+type privilegeTarget struct {
+	username  string
+	namespace string
+	table     string
+}
+
+// applyUserOptions applies the options given to CreateUser or AlterUser.
+func (u *mockUser) applyUserOptions(options []pb.UserOption) {
+	for _, option := range options {
+		switch option {
+		case pb.UserOption_USER_OPTION_SUPERUSER:
+			u.superuser = true
+		case pb.UserOption_USER_OPTION_NO_SUPERUSER:
+			u.superuser = false
+		}
+	}
+}
+
+// lookupUser returns a user, or NotFound if it does not exist. The caller must hold mu.
+func (s *Server) lookupUser(username string) (*mockUser, error) {
+	user, ok := s.users[username]
+	if !ok {
+		return nil, status.Errorf(codes.NotFound, "user %s does not exist", username)
+	}
+	return user, nil
+}
+
+// privilegeTarget checks that the user and the namespace or table of a Grant, Revoke or
+// GetPrivileges request exist. The caller must hold mu.
+func (s *Server) privilegeTarget(username, namespace string, table *string) (privilegeTarget, error) {
+	target := privilegeTarget{username: username, namespace: namespace}
+	if _, err := s.lookupUser(username); err != nil {
+		return target, err
+	}
+	if table == nil {
+		return target, s.namespaceNotFound(namespace)
+	}
+	target.table = *table
+	_, err := s.lookupTable(namespace, *table)
+	return target, err
+}
+
+// dropPrivileges removes the privileges whose target matches. The caller must hold mu.
+func (s *Server) dropPrivileges(match func(privilegeTarget) bool) {
+	for target := range s.privileges {
+		if match(target) {
+			delete(s.privileges, target)
+		}
+	}
+}
+
+// CreateUser implements the CreateUser RPC.
+func (s *Server) CreateUser(ctx context.Context, req *pb.CreateUserRequest) (*pb.CreateUserResponse, error) {
+	s.logf("CreateUser: %s", req.Username)
+	if err := requireName("user", req.Username); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, exists := s.users[req.Username]; exists {
+		return nil, status.Errorf(codes.AlreadyExists, "user %s already exists", req.Username)
+	}
+	user := &mockUser{password: req.Password}
+	user.applyUserOptions(req.UserOptions)
+	s.users[req.Username] = user
+	return &pb.CreateUserResponse{}, nil
+}
+
+// AlterUser implements the AlterUser RPC.
+func (s *Server) AlterUser(ctx context.Context, req *pb.AlterUserRequest) (*pb.AlterUserResponse, error) {
+	s.logf("AlterUser: %s", req.Username)
+	s.mu.Lock()
+	defer s.unlock()
+
+	user, err := s.lookupUser(req.Username)
+	if err != nil {
+		return nil, err
+	}
+	if req.Password != nil {
+		user.password = req.Password
+	}
+	user.applyUserOptions(req.UserOptions)
+	return &pb.AlterUserResponse{}, nil
+}
+
+// DropUser implements the DropUser RPC.
+func (s *Server) DropUser(ctx context.Context, req *pb.DropUserRequest) (*pb.DropUserResponse, error) {
+	s.logf("DropUser: %s", req.Username)
+	s.mu.Lock()
+	defer s.unlock()
+
+	if _, err := s.lookupUser(req.Username); err != nil {
+		return nil, err
+	}
+	delete(s.users, req.Username)
+	s.dropPrivileges(func(target privilegeTarget) bool { return target.username == req.Username })
+	for _, policy := range s.policies {
+		delete(policy.userTags, req.Username)
+	}
+	return &pb.DropUserResponse{}, nil
+}
+
+// Grant implements the Grant RPC.
+func (s *Server) Grant(ctx context.Context, req *pb.GrantRequest) (*pb.GrantResponse, error) {
+	s.logf("Grant: %v", req)
+	if len(req.Privileges) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "at least one privilege must be given")
+	}
+	for _, privilege := range req.Privileges {
+		if privilege == pb.Privilege_PRIVILEGE_UNSPECIFIED {
+			return nil, status.Error(codes.InvalidArgument, "privilege is not specified")
+		}
+	}
+
+	s.mu.Lock()
+	defer s.unlock()
+
+	target, err := s.privilegeTarget(req.Username, req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	privileges := s.privileges[target]
+	if privileges == nil {
+		privileges = make(map[pb.Privilege]bool)
+		s.privileges[target] = privileges
+	}
+	for _, privilege := range req.Privileges {
+		privileges[privilege] = true
+	}
+	return &pb.GrantResponse{}, nil
+}
+
+// Revoke implements the Revoke RPC.
+func (s *Server) Revoke(ctx context.Context, req *pb.RevokeRequest) (*pb.RevokeResponse, error) {
+	s.logf("Revoke: %v", req)
+	s.mu.Lock()
+	defer s.unlock()
+
+	target, err := s.privilegeTarget(req.Username, req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	privileges := s.privileges[target]
+	for _, privilege := range req.Privileges {
+		delete(privileges, privilege)
+	}
+	if len(privileges) == 0 {
+		delete(s.privileges, target)
+	}
+	return &pb.RevokeResponse{}, nil
+}
+
+// GetUser implements the GetUser RPC. The user is left unset if it does not exist.
+func (s *Server) GetUser(ctx context.Context, req *pb.GetUserRequest) (*pb.GetUserResponse, error) {
+	s.logf("GetUser: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	user, ok := s.users[req.Username]
+	if !ok {
+		return &pb.GetUserResponse{}, nil
+	}
+	return &pb.GetUserResponse{User: &pb.User{Name: req.Username, Superuser: user.superuser}}, nil
+}
+
+// GetUsers implements the GetUsers RPC.
+func (s *Server) GetUsers(ctx context.Context, req *pb.GetUsersRequest) (*pb.GetUsersResponse, error) {
+	s.logf("GetUsers: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	resp := &pb.GetUsersResponse{}
+	for _, name := range sortedKeys(s.users) {
+		resp.Users = append(resp.Users, &pb.User{Name: name, Superuser: s.users[name].superuser})
+	}
+	return resp, nil
+}
+
+// GetCurrentUser implements the GetCurrentUser RPC. Unless the server requires auth, the
+// current user is always the default superuser.
+func (s *Server) GetCurrentUser(ctx context.Context, req *pb.GetCurrentUserRequest) (*pb.GetCurrentUserResponse, error) {
+	s.logf("GetCurrentUser: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	username := currentUser(ctx)
+	user, err := s.lookupUser(username)
+	if err != nil {
+		return nil, err
+	}
+	return &pb.GetCurrentUserResponse{User: &pb.User{Name: username, Superuser: user.superuser}}, nil
+}
+
+// GetPrivileges implements the GetPrivileges RPC.
+func (s *Server) GetPrivileges(ctx context.Context, req *pb.GetPrivilegesRequest) (*pb.GetPrivilegesResponse, error) {
+	s.logf("GetPrivileges: %v", req)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	target, err := s.privilegeTarget(req.Username, req.NamespaceName, req.TableName)
+	if err != nil {
+		return nil, err
+	}
+	resp := &pb.GetPrivilegesResponse{}
+	for privilege := range s.privileges[target] {
+		resp.Privileges = append(resp.Privileges, privilege)
+	}
+	sort.Slice(resp.Privileges, func(i, j int) bool {
+		return resp.Privileges[i] < resp.Privileges[j]
+	})
+	return resp, nil
+}
@@ -43,7 +43,7 @@ echo "Current directory: $(pwd)"
 
 # モックサーバーをビルド
 echo "Building mock server..."
-go build -o mock_server ./mock/mock_server.go || {
+go build -o mock_server ./mock/cmd/mock_server || {
     echo "Failed to build mock server"
     exit 1
 }
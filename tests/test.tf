@@ -40,10 +40,13 @@ variable "scalardb_password" {
 }
 
 resource "scalardb_namespace" "test" {
-  name               = "test_namespace"
-  replication_factor = 3
-  strategy_class     = "SimpleStrategy"
-  durable_writes     = true
+  name           = "test_namespace"
+  durable_writes = true
+
+  cassandra {
+    replication_strategy = "SimpleStrategy"
+    replication_factor   = 3
+  }
 }
 
 resource "scalardb_table" "users" {
@@ -77,7 +80,9 @@ resource "scalardb_table" "users" {
     type = "INT"
   }
 
-  compaction_strategy = "SizeTieredCompactionStrategy"
+  cassandra {
+    compaction_strategy = "STCS"
+  }
 
   clustering_order = {
     created_at = "DESC"
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
+// allKnown reports whether none of the values is unknown.
+func allKnown(values ...attr.Value) bool {
+	for _, v := range values {
+		if v.IsUnknown() {
+			return false
+		}
+	}
+	return true
+}
+
+// stringMapValue converts a string map to a map value.
+func stringMapValue(m map[string]string) types.Map {
+	elements := make(map[string]attr.Value, len(m))
+	for key, value := range m {
+		elements[key] = types.StringValue(value)
+	}
+	return types.MapValueMust(types.StringType, elements)
+}
+
+// stringListValue converts a string slice to a list value.
+func stringListValue(list []string) types.List {
+	elements := make([]attr.Value, 0, len(list))
+	for _, s := range list {
+		elements = append(elements, types.StringValue(s))
+	}
+	return types.ListValueMust(types.StringType, elements)
+}
+
+// stringSetValue converts a string slice to a set value.
+func stringSetValue(list []string) types.Set {
+	elements := make([]attr.Value, 0, len(list))
+	for _, s := range list {
+		elements = append(elements, types.StringValue(s))
+	}
+	return types.SetValueMust(types.StringType, elements)
+}
+
+// listStrings returns the known string elements of a list or set.
+func listStrings(elements []attr.Value) []string {
+	list := make([]string, 0, len(elements))
+	for _, element := range elements {
+		if s, ok := element.(types.String); ok && !s.IsNull() && !s.IsUnknown() {
+			list = append(list, s.ValueString())
+		}
+	}
+	return list
+}
+
+// boolValueOrDefault returns a bool value, replacing null with a default.
+func boolValueOrDefault(v types.Bool, defaultValue bool) types.Bool {
+	if v.IsNull() {
+		return types.BoolValue(defaultValue)
+	}
+	return v
+}
+
+// boolPointer returns a pointer to the value of a bool, or nil if it is null or unknown.
+func boolPointer(v types.Bool) *bool {
+	if v.IsNull() || v.IsUnknown() {
+		return nil
+	}
+	b := v.ValueBool()
+	return &b
+}
+
+// nullIfEmpty returns a null map for empty maps.
+func nullIfEmpty(m types.Map) types.Map {
+	if !m.IsUnknown() && len(m.Elements()) == 0 {
+		return types.MapNull(m.ElementType(nil))
+	}
+	return m
+}